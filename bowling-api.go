@@ -1,71 +1,1079 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log/slog"
+	"math"
+	"mime"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
-// Game contains the state of a bowling game.
+// ErrInvalidPinCount is returned by Roll when pins is outside the range a
+// single throw can knock down.
+var ErrInvalidPinCount = errors.New("invalid pin count")
+
+// ErrFrameOverfill is returned by Roll when the second ball of a frame would
+// knock down more pins than the first ball left standing.
+var ErrFrameOverfill = errors.New("frame exceeds the number of pins")
+
+// ErrGameOver is returned by Roll once all ten frames have been bowled.
+var ErrGameOver = errors.New("game is over")
+
+// ErrInvalidGameState is returned when decoding a game whose recorded state
+// is inconsistent.
+var ErrInvalidGameState = errors.New("invalid game state")
+
+// ErrNothingToUndo is returned by Undo when no rolls have been made.
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// ErrRollNotFound is returned by EditRoll when no roll has been made at the
+// given index.
+var ErrRollNotFound = errors.New("roll not found")
+
+// ErrPositionMismatch is returned when a roll asserts a frame or ball other
+// than the one the game is at.
+var ErrPositionMismatch = errors.New("roll position mismatch")
+
+// PositionError reports the frame and ball a game is actually at when a roll
+// asserts another. It wraps ErrPositionMismatch.
+type PositionError struct {
+	// Frame and Ball are the one-based position of the next ball.
+	Frame, Ball int
+}
+
+func (e *PositionError) Error() string {
+	return fmt.Sprintf("%v: the next ball is ball %d of frame %d", ErrPositionMismatch, e.Ball, e.Frame)
+}
+
+func (e *PositionError) Unwrap() error {
+	return ErrPositionMismatch
+}
+
+// ErrNotFirstBall is returned by ProjectStrikeAndSpare when the next ball is
+// not the first of its frame.
+var ErrNotFirstBall = errors.New("not the first ball of a frame")
+
+// Game contains the state of a bowling game. It is safe for concurrent use.
 type Game struct {
+	mu      sync.RWMutex
 	rolls   []int
 	current int
+
+	// events carries a ScoreEvent to subscribers after every roll. It is
+	// the GameStore's Broker once the game is stored, and nil in the copies
+	// made to project scores.
+	events *Broker
+
+	// cachedScore holds one more than the result of computeScore, or 0 once
+	// the rolls have changed. It is atomic so that Score calls sharing the
+	// read lock can fill it without racing.
+	cachedScore atomic.Int64
+
+	// rules is the variant the game is scored by, and rack its RackValue.
+	rules Rules
+	rack  int
+
+	// noTap is the first-ball pin count that counts as a strike, or 0 for a
+	// standard game.
+	noTap int
+
+	// name is the player bowling the game, if known.
+	name string
+
+	// id is the game's identifier in a GameStore, used to label its logs.
+	id string
+
+	// history holds when each of the rolls made so far was recorded, by now
+	// if it is set and time.Now if not.
+	history []time.Time
+	now     func() time.Time
+
+	// onComplete, if set, is called each time the game becomes complete;
+	// finished records that it has been since the last truncate.
+	onComplete func(CompletedGame)
+	finished   bool
+
+	// keys remembers the Idempotency-Key of recent rolls made with RollOnce.
+	keys keyCache
+
+	// notes holds the note set on each frame with SetNote.
+	notes [framesPerGame]string
+}
+
+// GameOption configures a Game created by NewGame.
+type GameOption func(*Game)
+
+// WithNoTap makes a no-tap game, where a ball that knocks down at least pins
+// of a full rack is scored as a strike. A pins value outside 1 to one less
+// than the rack, 9 in ten-pin, leaves the game standard. It must follow any
+// WithRules option.
+func WithNoTap(pins int) GameOption {
+	return func(gm *Game) {
+		if pins > 0 && pins < gm.rack {
+			gm.noTap = pins
+		}
+	}
+}
+
+// WithOnComplete has fn called with the game's final state each time it
+// becomes complete: the first time, and again after a reset or undo takes it
+// back out of the tenth frame. fn is called with the game locked, so it must
+// not call back into the game.
+func WithOnComplete(fn func(CompletedGame)) GameOption {
+	return func(gm *Game) {
+		gm.onComplete = fn
+	}
+}
+
+// WithClock has the game timestamp its rolls by now instead of time.Now.
+func WithClock(now func() time.Time) GameOption {
+	return func(gm *Game) {
+		gm.now = now
+	}
 }
 
-// NewGame allocates and starts a new game of bowling.
-func NewGame() *Game {
-	game := new(Game)
-	game.rolls = make([]int, maxThrowsPerGame)
+// gamePool holds games given back with ReleaseGame, so NewGame can reuse
+// their rolls instead of allocating new ones.
+var gamePool = sync.Pool{
+	New: func() any { return new(Game) },
+}
+
+// NewGame starts a new game of bowling, reusing a released game if there is
+// one.
+func NewGame(opts ...GameOption) *Game {
+	game := gamePool.Get().(*Game)
+	game.events = new(Broker)
+	game.setRules(TenPin)
+	for _, opt := range opts {
+		opt(game)
+	}
 	return game
 }
 
+// ReleaseGame resets gm and returns it to the pool NewGame draws from. gm
+// must not be used again, and any Subscribe channels on it are abandoned
+// without being closed.
+func ReleaseGame(gm *Game) {
+	gm.mu.Lock()
+	rolls, history := gm.rolls[:0], gm.history[:0]
+	gm.mu.Unlock()
+	*gm = Game{rolls: rolls, history: history}
+	gamePool.Put(gm)
+}
+
+// NewGameFromRolls starts a game configured by opts and replays rolls into
+// it, so play can resume where the sequence left off. It returns an error
+// wrapping ErrInvalidGameState and the rejected roll's error if any roll is
+// out of range, overfills its frame, or comes after the game is complete.
+func NewGameFromRolls(rolls []int, opts ...GameOption) (*Game, error) {
+	gm := NewGame(opts...)
+	if _, err := gm.rollEach(rolls); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidGameState, err)
+	}
+	return gm, nil
+}
+
+// ValidateRolls checks that rolls is a legal sequence of balls from the start
+// of a standard game. If it is not, it returns the zero-based index of the
+// first illegal roll, whether out of range, overfilling its frame or after
+// the game is complete, and an error describing it; otherwise it returns -1
+// and nil.
+func ValidateRolls(rolls []int) (firstBadIndex int, err error) {
+	return NewGame().rollEach(rolls)
+}
+
+// Validate checks that the rolls recorded obey the rules of the game, as
+// they would have had they been rolled one by one, to catch a corrupt saved
+// game. It returns an error wrapping ErrInvalidGameState that names the
+// first offending frame and roll.
+func (gm *Game) Validate() error {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return validateGame(gm.rules, gm.noTap, gm.rolls[:gm.current])
+}
+
+// validateGame is Validate for a game following rules with the given no-tap
+// count that has recorded rolls.
+func validateGame(rules Rules, noTap int, rolls []int) error {
+	replay := NewGame(WithRules(rules), WithNoTap(noTap))
+	defer ReleaseGame(replay)
+	for x, pins := range rolls {
+		frame, _ := replay.cursor()
+		if err := replay.roll(pins); err != nil {
+			return fmt.Errorf("%w: roll %d in frame %d: %w", ErrInvalidGameState, x+1, frame+1, err)
+		}
+	}
+	return nil
+}
+
+// rollEach rolls each of rolls in order, stopping at the first one rejected.
+// It returns that roll's index and an error naming it, or -1 and nil if all
+// were rolled. The caller must hold gm.mu for writing.
+func (gm *Game) rollEach(rolls []int) (int, error) {
+	for x, pins := range rolls {
+		if err := gm.roll(pins); err != nil {
+			return x, fmt.Errorf("roll %d of %d: %w", x+1, len(rolls), err)
+		}
+	}
+	return -1, nil
+}
+
 // Roll rolls the ball and knocks down the number of pins specified by pins.
-func (gm *Game) Roll(pins int) {
+// In a no-tap game a ball at a full rack that reaches the no-tap count is
+// recorded as a strike. Leaving the game untouched, it returns
+// ErrInvalidPinCount if pins is negative, greater than the number of pins on
+// the lane or not a total the pins left standing can make, ErrFrameOverfill
+// if pins is more than the first ball of the frame left standing, and
+// ErrGameOver if the tenth frame has already been resolved.
+func (gm *Game) Roll(pins int) error {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	frame, _ := gm.cursor()
+	if err := gm.roll(pins); err != nil {
+		return err
+	}
+	slog.Debug("roll", "game", gm.id, "pins", pins)
+	gm.recorded(1, frame)
+	return nil
+}
+
+// RollAt rolls pins as Roll does, but only if the next ball is ball number
+// ball of frame number frame, both counted from 1 as CurrentFrame and
+// BallInFrame report them; a frame or ball of 0 is not checked. Otherwise it
+// returns a *PositionError naming where the game actually is, and rolls
+// nothing.
+func (gm *Game) RollAt(frame, ball, pins int) error {
+	_, _, err := gm.rollOnce("", func() (int, error) {
+		return pins, gm.checkPosition(frame, ball)
+	})
+	return err
+}
+
+// checkPosition checks that the next ball is the one RollAt asserts; the
+// caller must hold gm.mu. Once the game is complete there is no next ball, so
+// it leaves rejecting the roll to roll.
+func (gm *Game) checkPosition(frame, ball int) error {
+	if gm.isComplete() {
+		return nil
+	}
+	at, start := gm.cursor()
+	atBall := gm.current - start + 1
+	if frame != 0 && frame != at+1 || ball != 0 && ball != atBall {
+		return &PositionError{Frame: at + 1, Ball: atBall}
+	}
+	return nil
+}
+
+// RollAll rolls each of pins in order. The batch is atomic: if any roll is
+// rejected, the error names it and none of the batch is recorded.
+func (gm *Game) RollAll(pins []int) error {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	start := gm.current
+	if _, err := gm.rollEach(pins); err != nil {
+		gm.truncate(start)
+		return err
+	}
+	if len(pins) > 0 {
+		gm.recorded(len(pins), len(gm.frames())-1)
+	}
+	return nil
+}
+
+// recorded updates metrics and notifies subscribers after n rolls, the last
+// of them in the zero-based frame; the caller must hold gm.mu.
+func (gm *Game) recorded(n, frame int) {
+	rollsTotal.Add(float64(n))
+	score := gm.score()
+	if gm.isComplete() {
+		gameScore.Observe(float64(score))
+		if !gm.finished && gm.onComplete != nil {
+			gm.onComplete(CompletedGame{ID: gm.id, Score: score, Frames: gm.frameScores()})
+		}
+		gm.finished = true
+	}
+	gm.events.Publish(gm.id, ScoreEvent{Score: score, Frame: frame + 1})
+}
+
+// roll is Roll without locking; the caller must hold gm.mu.
+func (gm *Game) roll(pins int) error {
+	if pins < 0 || pins > gm.rack {
+		return ErrInvalidPinCount
+	}
+	if gm.isComplete() {
+		return ErrGameOver
+	}
+	if gm.noTap > 0 && pins >= gm.noTap && gm.freshRack() {
+		pins = gm.rack
+	}
+	frame, _ := gm.cursor()
+	standing := gm.standingPins()
+	if frame < framesPerGame-1 && pins > standing {
+		return fmt.Errorf("%w: %d with %d standing in frame %d", ErrFrameOverfill, pins, standing, frame+1)
+	}
+	if pins <= standing && !gm.knockable(pins) {
+		return fmt.Errorf("%w: no pins standing add up to %d", ErrInvalidPinCount, pins)
+	}
 	gm.rolls[gm.current] = pins
 	gm.current++
+	gm.history = append(gm.history, gm.timeNow())
+	gm.invalidateScore()
+	if frame == framesPerGame-1 {
+		if err := gm.validateTenthFrame(); err != nil {
+			gm.truncate(gm.current - 1)
+			return err
+		}
+	}
+	return nil
+}
+
+// truncate discards every roll from the zero-based index n onward; the
+// caller must hold gm.mu for writing. A game taken back out of its tenth
+// frame calls onComplete again when it is next complete, while one only
+// losing tenth-frame balls does not, so correcting the last ball doesn't
+// report the game twice.
+func (gm *Game) truncate(n int) {
+	for gm.current > n {
+		gm.current--
+		gm.rolls[gm.current] = 0
+	}
+	if len(gm.history) > n {
+		gm.history = gm.history[:n]
+	}
+	gm.invalidateScore()
+	if frame, _ := gm.cursor(); frame < framesPerGame-1 {
+		gm.finished = false
+	}
+}
+
+// timeNow returns the current time by the game's clock.
+func (gm *Game) timeNow() time.Time {
+	if gm.now == nil {
+		return time.Now()
+	}
+	return gm.now()
+}
+
+// validateTenthFrame checks the balls bowled so far in the tenth frame; the
+// caller must hold gm.mu. The tenth frame has up to three balls: pins are set
+// up afresh after a strike or spare, and otherwise each ball can only knock
+// down what the previous one left standing.
+func (gm *Game) validateTenthFrame() error {
+	_, start := gm.cursor()
+	balls := gm.rolls[start:gm.current]
+	if len(balls) > 3 {
+		return fmt.Errorf("%w: %d balls in the tenth frame", ErrGameOver, len(balls))
+	}
+	standing := gm.rack
+	for x, pins := range balls {
+		if pins > standing {
+			return fmt.Errorf("%w: ball %d knocks down %d pins with %d standing in frame %d", ErrFrameOverfill, x+1, pins, standing, framesPerGame)
+		}
+		standing -= pins
+		if standing == 0 {
+			standing = gm.rack
+		}
+	}
+	return nil
+}
+
+// Finish completes an abandoned game by rolling a gutter ball for every ball
+// still to be bowled, and returns the final score. Unlike Reset it keeps the
+// rolls made so far, and once finished the game rejects further rolls with
+// ErrGameOver.
+func (gm *Game) Finish() int {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	n, frame := 0, 0
+	for !gm.isComplete() {
+		frame, _ = gm.cursor()
+		gm.roll(0)
+		n++
+	}
+	if n > 0 {
+		gm.recorded(n, frame)
+	}
+	return gm.score()
+}
+
+// Undo takes back the last roll so it can be rolled again. It returns
+// ErrNothingToUndo if no rolls have been made.
+func (gm *Game) Undo() error {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	if gm.current == 0 {
+		return ErrNothingToUndo
+	}
+	gm.truncate(gm.current - 1)
+	return nil
+}
+
+// ResetFrame takes back every ball already rolled in the frame in progress,
+// leaving earlier frames alone, so the frame can be bowled again. It returns
+// ErrNothingToUndo if the frame has no balls yet, and ErrGameOver once the
+// game is complete.
+func (gm *Game) ResetFrame() error {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	if gm.isComplete() {
+		return ErrGameOver
+	}
+	frame, start := gm.cursor()
+	if gm.current == start {
+		return fmt.Errorf("%w: frame %d has no balls", ErrNothingToUndo, frame+1)
+	}
+	gm.truncate(start)
+	return nil
+}
+
+// EditRoll corrects the roll at the zero-based index to pins, as a scorer
+// fixing a mistake would. Every roll from the edit onward is re-validated, so
+// an edit that overfills its own frame or leaves a later frame illegal is
+// rejected and the game is left untouched. It returns ErrRollNotFound if no
+// roll has been made at index.
+func (gm *Game) EditRoll(index, pins int) error {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	if index < 0 || index >= gm.current {
+		return fmt.Errorf("%w: index %d with %d rolls made", ErrRollNotFound, index, gm.current)
+	}
+
+	replay := append([]int(nil), gm.rolls[index:gm.current]...)
+	replay[0] = pins
+	edited := gm.clone()
+	edited.truncate(index)
+	for x, p := range replay {
+		if err := edited.roll(p); err != nil {
+			return fmt.Errorf("editing roll %d: replaying roll %d: %w", index, index+x, err)
+		}
+	}
+
+	// The corrected rolls keep the times they were first recorded.
+	gm.rolls, gm.current = edited.rolls, edited.current
+	gm.invalidateScore()
+	frame, _ := gm.cursor()
+	gm.events.Publish(gm.id, ScoreEvent{Score: gm.score(), Frame: frame + 1})
+	return nil
+}
+
+// Name returns the name of the player bowling the game, or "" if unset.
+func (gm *Game) Name() string {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.name
+}
+
+// ID returns the game's ID in its GameStore, or "" if it isn't stored.
+func (gm *Game) ID() string {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.id
+}
+
+// SetName records the name of the player bowling the game. It returns
+// ErrInvalidName, leaving the name unchanged, if name is blank or longer
+// than 64 characters.
+func (gm *Game) SetName(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	gm.name = name
+	return nil
+}
+
+// Rolls returns a copy of the rolls made so far.
+func (gm *Game) Rolls() []int {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	rolls := make([]int, gm.current)
+	copy(rolls, gm.rolls)
+	return rolls
+}
+
+// Clone returns an independent copy of the game, so rolls made on the copy
+// don't affect the original. Event subscribers are not copied.
+func (gm *Game) Clone() *Game {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	c := gm.clone()
+	c.events = new(Broker)
+	return c
+}
+
+// clone is Clone without locking; the caller must hold gm.mu.
+func (gm *Game) clone() *Game {
+	c := &Game{
+		rolls:   make([]int, len(gm.rolls)),
+		current: gm.current,
+		rules:   gm.rules,
+		rack:    gm.rack,
+		noTap:   gm.noTap,
+		history: append([]time.Time(nil), gm.history...),
+		now:     gm.now,
+		notes:   gm.notes,
+	}
+	copy(c.rolls, gm.rolls)
+	return c
+}
+
+// Reset clears every roll so the game can be played again from the start.
+func (gm *Game) Reset() {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	gm.truncate(0)
+	gm.keys = keyCache{}
+	gm.notes = [framesPerGame]string{}
+}
+
+// gameJSON is the serialized form of a Game.
+type gameJSON struct {
+	Name    string   `json:"name,omitempty"`
+	Rolls   []int    `json:"rolls"`
+	Current int      `json:"current"`
+	NoTap   int      `json:"no_tap,omitempty"`
+	Rules   *Rules   `json:"rules,omitempty"`
+	Notes   []string `json:"notes,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, emitting only the rolls made so far.
+func (gm *Game) MarshalJSON() ([]byte, error) {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	state := gameJSON{
+		Name:    gm.name,
+		Rolls:   gm.rolls[:gm.current],
+		Current: gm.current,
+		NoTap:   gm.noTap,
+	}
+	if gm.rules.Name != TenPin.Name {
+		state.Rules = &gm.rules
+	}
+	if gm.notes != [framesPerGame]string{} {
+		state.Notes = gm.notes[:]
+	}
+	return json.Marshal(state)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the game's state with
+// the decoded rolls. Rolls that aren't a legal game, as Validate checks, are
+// rejected with ErrInvalidGameState and leave the game untouched.
+func (gm *Game) UnmarshalJSON(data []byte) error {
+	var state gameJSON
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	rules := TenPin
+	if state.Rules != nil {
+		rules = *state.Rules
+	}
+	if !rules.valid() {
+		return fmt.Errorf("%w: unplayable rules %+v", ErrInvalidGameState, rules)
+	}
+	if state.Current != len(state.Rolls) || state.Current > rules.maxBalls() {
+		return fmt.Errorf("%w: %d rolls with current of %d", ErrInvalidGameState, len(state.Rolls), state.Current)
+	}
+	if err := validateGame(rules, state.NoTap, state.Rolls); err != nil {
+		return err
+	}
+	if len(state.Notes) > framesPerGame {
+		return fmt.Errorf("%w: notes on %d frames", ErrInvalidGameState, len(state.Notes))
+	}
+	if state.Name != "" {
+		if err := validateName(state.Name); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidGameState, err)
+		}
+	}
+
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	if gm.events == nil {
+		gm.events = new(Broker)
+	}
+	gm.setRules(rules)
+	copy(gm.rolls, state.Rolls)
+	gm.current = state.Current
+	gm.history = make([]time.Time, state.Current)
+	gm.invalidateScore()
+	gm.noTap = 0
+	WithNoTap(state.NoTap)(gm)
+	gm.notes = [framesPerGame]string{}
+	copy(gm.notes[:], state.Notes)
+	gm.name = state.Name
+	return nil
 }
 
 // Score calculates and returns the player's current score.
-func (gm *Game) Score() (sum int) {
+func (gm *Game) Score() int {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.score()
+}
+
+// score is Score without locking; the caller must hold gm.mu. The score is
+// only recomputed when the rolls have changed since it was last read.
+func (gm *Game) score() int {
+	if cached := gm.cachedScore.Load(); cached != 0 {
+		return int(cached - 1)
+	}
+	score := gm.computeScore()
+	gm.cachedScore.Store(int64(score) + 1)
+	return score
+}
+
+// invalidateScore discards the cached score; the caller must hold gm.mu for
+// writing.
+func (gm *Game) invalidateScore() {
+	gm.cachedScore.Store(0)
+}
+
+// computeScore walks every frame to total the score; the caller must hold
+// gm.mu.
+func (gm *Game) computeScore() (sum int) {
 	for throw, frame := 0, 0; frame < framesPerGame; frame++ {
-		if gm.isStrike(throw) {
-			sum += gm.strikeBonusFor(throw)
-			throw += 1
-		} else if gm.isSpare(throw) {
-			sum += gm.spareBonusFor(throw)
-			throw += 2
+		points, next, _ := gm.frameAt(frame, throw)
+		sum += points
+		throw = next
+	}
+	return sum
+}
+
+// ScoreWithHandicap returns the scratch score plus a league handicap of
+// (base - average) * pct, rounded down. A bowler averaging above base gets
+// no handicap rather than a negative one.
+func (gm *Game) ScoreWithHandicap(average, base int, pct float64) int {
+	return gm.Score() + handicap(average, base, pct)
+}
+
+// handicap computes (base - average) * pct, rounded down and never negative.
+// A small epsilon keeps products like 30 * 0.7 from rounding down a whole pin.
+func handicap(average, base int, pct float64) int {
+	h := int(math.Floor(float64(base-average)*pct + 1e-9))
+	if h < 0 {
+		return 0
+	}
+	return h
+}
+
+// ETag returns a weak entity tag for the game's current state, derived from
+// the number of rolls, the score and a hash of the rolls themselves, the
+// rules, the player's name and the frame notes, along with that score.
+func (gm *Game) ETag() (etag string, score int) {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	score = gm.score()
+	state := fnv.New64a()
+	fmt.Fprintf(state, "%v|%v|%d|%q|%q", gm.rolls[:gm.current], gm.rules, gm.noTap, gm.name, gm.notes)
+	return fmt.Sprintf(`W/"%d-%d-%x"`, gm.current, score, state.Sum64()), score
+}
+
+// variantETag returns etag for another representation of the same state, so
+// that a cached copy of one doesn't satisfy a request for the other.
+func variantETag(etag, variant string) string {
+	return strings.TrimSuffix(etag, `"`) + "-" + variant + `"`
+}
+
+// MaxPossibleScore returns the highest score still reachable, assuming every
+// remaining ball knocks down all of the pins left standing.
+func (gm *Game) MaxPossibleScore() int {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.maxPossibleScore()
+}
+
+// maxPossibleScore is MaxPossibleScore without locking; the caller must hold
+// gm.mu.
+func (gm *Game) maxPossibleScore() int {
+	best := gm.clone()
+	for !best.isComplete() {
+		best.roll(best.standingPins())
+	}
+	return best.score()
+}
+
+// ProjectStrikeAndSpare returns the highest score still reachable if the
+// upcoming frame is bowled as a strike, and if it is bowled as a spare with
+// the most pins a spare's first ball can take. It returns ErrNotFirstBall
+// unless the next ball starts a frame, and ErrGameOver once the game is
+// complete.
+func (gm *Game) ProjectStrikeAndSpare() (strikeScore, spareScore int, err error) {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	if gm.isComplete() {
+		return 0, 0, ErrGameOver
+	}
+	if frame, start := gm.cursor(); start != gm.current {
+		return 0, 0, fmt.Errorf("%w: ball %d of frame %d", ErrNotFirstBall, gm.current-start+1, frame+1)
+	}
+
+	strike := gm.clone()
+	strike.roll(gm.rack)
+	// Under a no-tap rule a first ball of noTap pins or more counts as a
+	// strike, so the best spare takes one fewer than that.
+	first := gm.rack - 1
+	if gm.noTap > 0 && gm.noTap <= gm.rack {
+		first = gm.noTap - 1
+	}
+	spare := gm.clone()
+	for first > 0 && spare.roll(first) != nil {
+		first--
+	}
+	spare.roll(spare.standingPins())
+	return strike.maxPossibleScore(), spare.maxPossibleScore(), nil
+}
+
+// PinsNeededFor returns how many more points the game must score to reach
+// target, and whether that is still possible given the highest score left
+// reachable. A target already reached needs 0.
+func (gm *Game) PinsNeededFor(target int) (int, bool) {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	score := gm.score()
+	if score >= target {
+		return 0, true
+	}
+	return target - score, gm.maxPossibleScore() >= target
+}
+
+// StandingPins returns how many pins are standing on the lane: the whole rack
+// at the start of a frame and whenever the tenth frame sets up a fresh one,
+// or what the earlier balls left standing mid-frame. Unlike NextRollMax it
+// still reports the pins left once the game is complete.
+func (gm *Game) StandingPins() int {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.standingPins()
+}
+
+// NextRollMax returns the most pins the next ball can legally knock down: the
+// whole rack when it is full, including after a strike or spare in the tenth
+// frame, or whatever the earlier balls of an open frame left standing. It
+// returns 0 once the game is complete.
+func (gm *Game) NextRollMax() int {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	if gm.isComplete() {
+		return 0
+	}
+	return gm.standingPins()
+}
+
+// RemainingBalls returns the most balls that can still legally be thrown:
+// every ball left in the first nine frames, since a strike there only ends a
+// frame early, plus the tenth frame's fill balls as if they were earned. It
+// returns 0 once the game is complete.
+func (gm *Game) RemainingBalls() int {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	longest := gm.clone()
+	for !longest.isComplete() {
+		if frame, _ := longest.cursor(); frame < framesPerGame-1 {
+			longest.roll(0)
 		} else {
-			sum += gm.framePointsAt(throw)
-			throw += 2
+			longest.roll(longest.standingPins())
 		}
 	}
-	return sum
+	return longest.current - gm.current
+}
+
+// FrameScores returns the running total after each frame, as it would be
+// written on a paper scorecard. A frame is omitted until it and any bonus
+// balls it is owed have been bowled.
+func (gm *Game) FrameScores() []int {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.frameScores()
+}
+
+// frameScores is FrameScores without locking; the caller must hold gm.mu.
+func (gm *Game) frameScores() []int {
+	scores := make([]int, 0, framesPerGame)
+	sum := 0
+	for throw, frame := 0, 0; frame < framesPerGame; frame++ {
+		if throw >= gm.current {
+			return scores
+		}
+		points, next, last := gm.frameAt(frame, throw)
+		if last >= gm.current {
+			return scores
+		}
+		sum += points
+		scores = append(scores, sum)
+		throw = next
+	}
+	return scores
+}
+
+// frames splits the rolls made so far into frames; the caller must hold
+// gm.mu. The tenth frame includes its fill balls, and a frame in progress
+// holds only the balls bowled so far.
+func (gm *Game) frames() [][]int {
+	frames := make([][]int, 0, framesPerGame)
+	for throw, frame := 0, 0; frame < framesPerGame && throw < gm.current; frame++ {
+		end, _ := gm.frameEnd(throw)
+		if frame == framesPerGame-1 {
+			end = gm.current
+		}
+		balls := make([]int, end-throw)
+		copy(balls, gm.rolls[throw:end])
+		frames = append(frames, balls)
+		throw = end
+	}
+	return frames
+}
+
+// ScoreThrough returns the running total through the last fully resolved
+// frame, along with the number of such frames. A strike or spare whose bonus
+// balls have not yet been bowled is not resolved, so its frame is excluded.
+func (gm *Game) ScoreThrough() (score int, completeFrames int) {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.resolvedScore(), len(gm.frameScores())
+}
+
+// resolvedScore returns the running total through the last resolved frame;
+// the caller must hold gm.mu.
+func (gm *Game) resolvedScore() int {
+	frames := gm.frameScores()
+	if len(frames) == 0 {
+		return 0
+	}
+	return frames[len(frames)-1]
+}
+
+// ScoreAtBall returns the running total as it stood after only the first n
+// balls, as Replay reports it: a strike's or spare's points count once its
+// bonus balls are among those n. An n outside 0 to the number of balls
+// rolled is clamped to that range.
+func (gm *Game) ScoreAtBall(n int) int {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	prefix := gm.clone()
+	prefix.truncate(max(0, min(n, gm.current)))
+	return prefix.resolvedScore()
+}
+
+// Replay returns the running total as it stood after each ball, for stepping
+// through the game. As on a paper scorecard, a frame only counts once it is
+// resolved, so a strike's or spare's points appear at the ball that
+// completes its bonus.
+func (gm *Game) Replay() []int {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	totals := make([]int, gm.current)
+	prefix := gm.clone()
+	for n := gm.current; n > 0; n-- {
+		prefix.truncate(n)
+		totals[n-1] = prefix.resolvedScore()
+	}
+	return totals
+}
+
+// IsComplete reports whether all ten frames have been bowled. A strike in
+// the tenth frame needs two fill balls and a spare needs one before the game
+// is complete.
+func (gm *Game) IsComplete() bool {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.isComplete()
+}
+
+// CurrentFrame returns the one-based number of the frame the next ball
+// belongs to. Once the game is complete it stays at the tenth frame.
+func (gm *Game) CurrentFrame() int {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	frame, _ := gm.cursor()
+	return frame + 1
+}
+
+// BallInFrame returns the one-based number of the next ball within the
+// current frame: 1 or 2, or up to 3 in the tenth frame. It returns 0 once the
+// game is complete.
+func (gm *Game) BallInFrame() int {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	if gm.isComplete() {
+		return 0
+	}
+	_, start := gm.cursor()
+	return gm.current - start + 1
+}
+
+// cursor locates the frame the next throw belongs to. It returns the
+// zero-based frame index and the index into rolls at which that frame began.
+func (gm *Game) cursor() (frame, start int) {
+	throw := 0
+	for frame = 0; frame < framesPerGame-1; frame++ {
+		next, done := gm.frameEnd(throw)
+		if !done {
+			return frame, throw
+		}
+		throw = next
+	}
+	return frame, throw
+}
+
+// frameEnd finds the end of one of the first nine frames, which starts at
+// throw and ends when the rack is cleared or its balls run out. It returns
+// the index of the frame's last ball plus one, or gm.current and false if
+// the frame is still in progress.
+func (gm *Game) frameEnd(throw int) (end int, done bool) {
+	standing := gm.rack
+	for end = throw; end < throw+gm.rules.BallsPerFrame; end++ {
+		if end >= gm.current {
+			return gm.current, false
+		}
+		standing -= gm.rolls[end]
+		if standing == 0 {
+			return end + 1, true
+		}
+	}
+	return end, true
+}
+
+// standingPins returns the number of pins standing for the next throw; the
+// caller must hold gm.mu. Pins are set up afresh after a strike or spare in
+// the tenth frame.
+func (gm *Game) standingPins() int {
+	standing, _ := gm.rackState()
+	return standing
+}
+
+// freshRack determines if the next throw will be at a full set of pins,
+// either as the first ball of a frame or after a strike or spare in the tenth;
+// the caller must hold gm.mu.
+func (gm *Game) freshRack() bool {
+	_, fresh := gm.rackState()
+	return fresh
+}
+
+// rackState returns the pins standing for the next throw and whether they
+// have just been set up; the caller must hold gm.mu.
+func (gm *Game) rackState() (standing int, fresh bool) {
+	frame, start := gm.cursor()
+	standing, fresh = gm.rack, true
+	for _, pins := range gm.rolls[start:gm.current] {
+		standing -= pins
+		fresh = false
+		if standing == 0 && frame == framesPerGame-1 {
+			standing, fresh = gm.rack, true
+		}
+	}
+	return standing, fresh
+}
+
+// isComplete determines if all ten frames, including any fill balls earned
+// in the tenth, have been bowled.
+func (gm *Game) isComplete() bool {
+	frame, start := gm.cursor()
+	if frame < framesPerGame-1 {
+		return false
+	}
+	balls := gm.current - start
+	if gm.isStrike(start) || gm.isSpare(start) {
+		return balls >= 3
+	}
+	return balls >= gm.rules.BallsPerFrame
+}
+
+// pinsAt returns the pins knocked down by a throw, or 0 for a throw that has
+// not been made, so look-aheads for bonus balls never read past the rolls
+// recorded.
+func (gm *Game) pinsAt(throw int) int {
+	if throw < 0 || throw >= gm.current || throw >= len(gm.rolls) {
+		return 0
+	}
+	return gm.rolls[throw]
+}
+
+// frameAt scores the zero-based frame whose first ball is throw. It returns
+// the frame's points with any bonus balls bowled so far, the index of the
+// next frame's first ball, and the index of the last ball the points depend
+// on.
+//
+// The tenth frame has no following frame to take bonus balls from. Instead a
+// strike or spare earns fill balls within the frame (X X X, X 5 3, 5/ X), and
+// the frame scores the sum of all of its balls, so X X X is 30.
+func (gm *Game) frameAt(frame, throw int) (points, next, last int) {
+	if frame == framesPerGame-1 {
+		return gm.tenthFrameAt(throw)
+	}
+	switch {
+	case gm.isStrike(throw):
+		return gm.strikeBonusFor(throw), throw + 1, throw + 2
+	case gm.isSpare(throw):
+		return gm.spareBonusFor(throw), throw + 2, throw + 2
+	}
+	balls := gm.rules.BallsPerFrame
+	for x := throw; x < throw+balls; x++ {
+		points += gm.pinsAt(x)
+	}
+	return points, throw + balls, throw + balls - 1
+}
+
+// tenthFrameAt scores the tenth frame, whose first ball is throw, by summing
+// the balls bowled in it, as frameAt does for the other frames.
+func (gm *Game) tenthFrameAt(throw int) (points, next, last int) {
+	for x := throw; x < gm.current; x++ {
+		points += gm.rolls[x]
+	}
+	last = throw + gm.rules.BallsPerFrame - 1
+	if gm.isStrike(throw) || gm.isSpare(throw) {
+		last = throw + 2
+	}
+	return points, gm.current, last
 }
 
 // isStrike determines if a given throw is a strike or not.
 // A strike is knocking down all pins in one throw.
 func (gm *Game) isStrike(throw int) bool {
-	return gm.rolls[throw] == allPins
+	return gm.pinsAt(throw) == gm.rack
 }
 
-// strikeBonusFor calculates and returns the strike bonus for a throw.
+// strikeBonusFor calculates and returns the strike bonus for a throw. Bonus
+// balls not yet bowled count as zero.
 func (gm *Game) strikeBonusFor(throw int) int {
-	return allPins + gm.framePointsAt(throw+1)
+	return gm.rack + gm.framePointsAt(throw+1)
 }
 
 // isSpare determines if a given frame is a spare or not.
 // A spare is knocking down all pins in one frame with two throws.
 func (gm *Game) isSpare(throw int) bool {
-	return gm.framePointsAt(throw) == allPins
+	return gm.framePointsAt(throw) == gm.rack
 }
 
-// spareBonusFor calculates and returns the spare bonus for a throw.
+// spareBonusFor calculates and returns the spare bonus for a throw. A bonus
+// ball not yet bowled counts as zero.
 func (gm *Game) spareBonusFor(throw int) int {
-	return allPins + gm.rolls[throw+2]
+	return gm.rack + gm.pinsAt(throw+2)
 }
 
-// framePointsAt computes and returns the score in a frame specified by throw.
+// framePointsAt computes and returns the points of the two balls starting at
+// throw.
 func (gm *Game) framePointsAt(throw int) int {
-	return gm.rolls[throw] + gm.rolls[throw+1]
+	return gm.pinsAt(throw) + gm.pinsAt(throw+1)
 }
 
 // testing utilities:
@@ -83,8 +1091,15 @@ func (gm *Game) rollStrike() {
 	gm.Roll(10)
 }
 
+// rollMixedGame bowls X 7/ 9- X -8 8/ -6 X X X81, a 167 game.
+func (gm *Game) rollMixedGame() {
+	for _, pins := range []int{10, 7, 3, 9, 0, 10, 0, 8, 8, 2, 0, 6, 10, 10, 10, 8, 1} {
+		gm.Roll(pins)
+	}
+}
+
 const (
-	// allPins is the number of pins allocated per fresh throw.
+	// allPins is the number of pins allocated per fresh throw in ten-pin.
 	allPins = 10
 
 	// framesPerGame is the numer of frames per bowling game.
@@ -92,65 +1107,986 @@ const (
 
 	// maxThrowsPerGame is the maximum number of throws possible in a single game.
 	maxThrowsPerGame = 21
-)
 
-// endpoint handlers:
+	// defaultAddr is the address the server listens on when none is configured.
+	defaultAddr = ":8080"
 
-// RollHandler handles the "POST /roll" endpoint.
+	// defaultGRPCAddr is the address the gRPC server listens on by default.
+	defaultGRPCAddr = ":9090"
+
+	// addrEnv is the environment variable consulted for the listen address.
+	addrEnv = "BOWLING_ADDR"
+
+	// maxBodyBytes bounds the size of a single-roll request body.
+	maxBodyBytes = 1 << 10
+
+	// shutdownTimeout bounds how long in-flight requests may run on shutdown.
+	shutdownTimeout = 10 * time.Second
+
+	// webhookAttempts and webhookBackoff control retries of the completed-game
+	// webhook.
+	webhookAttempts = 3
+	webhookBackoff  = time.Second
+)
+
+// endpoint handlers:
+
+// RollHandler handles the "POST /roll" endpoint. With a token query parameter
+// the roll is made on the game the token holds instead of gm, and the
+// response carries the updated token; an empty token starts a new game.
+// Otherwise a retried roll with the same Idempotency-Key header as an earlier
+// one is not recorded again, and gets the score the first one got. The body
+// gives the ball as {"pins": N} or, as RollMark takes it, {"mark": "strike"}
+// or {"mark": "spare"}, or as the form-encoded pins=N or mark=strike, and a
+// body giving both or neither is rejected with 400. It may also assert the
+// ball's position with "frame" and "ball", as RollAt does, and the roll is
+// rejected with 409 if the game is elsewhere, the error giving the game's
+// actual "frame" and "ball".
 func RollHandler(gm *Game) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
-			http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
 			return
 		}
+		query := r.URL.Query()
+		stateless := query.Has("token")
+		target := gm
+		if tok := query.Get("token"); tok != "" {
+			var err error
+			if target, err = GameFromToken(tok); err != nil {
+				writeGameError(w, err)
+				return
+			}
+		} else if stateless {
+			target = NewGame()
+		}
 
-		// Parse the pins from the request body
-		var roll struct {
-			Pins int `json:"pins"`
+		// Parse the pins or mark from the request body
+		var roll rollRequest
+		if err := decodeRoll(w, r, &roll); errors.Is(err, errUnsupportedMediaType) {
+			writeJSONError(w, http.StatusUnsupportedMediaType, "unsupported_media_type", err.Error())
+			return
+		} else if err != nil || (roll.Pins != nil) == (roll.Mark != "") {
+			writeJSONError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+			return
 		}
-		if err := json.NewDecoder(r.Body).Decode(&roll); err != nil {
-			http.Error(w, "Invalid request body", http.StatusBadRequest)
+		if roll.Frame < 0 || roll.Ball < 0 {
+			writeJSONError(w, http.StatusBadRequest, "invalid_body", "frame and ball must not be negative")
 			return
 		}
+		pins := 0
+		if roll.Pins != nil {
+			pins = *roll.Pins
+		}
 
-		gm.Roll(roll.Pins)
+		_, span := startSpan(r, "roll")
+		span.SetAttributes(attrGameID.String(target.ID()))
+		key := r.Header.Get("Idempotency-Key")
+		if stateless {
+			key = ""
+		}
+		score, _, err := target.rollOnce(key, func() (int, error) {
+			if err := target.checkPosition(roll.Frame, roll.Ball); err != nil {
+				return 0, err
+			}
+			if roll.Mark != "" {
+				var err error
+				pins, err = target.namedMarkPins(roll.Mark)
+				return pins, err
+			}
+			return pins, nil
+		})
+		span.SetAttributes(attrPins.Int(pins))
+		if err == nil {
+			span.SetAttributes(attrScore.Int(score))
+		}
+		endSpan(span, err)
+		if err != nil {
+			writeGameError(w, err)
+			return
+		}
 		w.WriteHeader(http.StatusCreated)
 
-		score := gm.Score()
-
 		//Convert the score to a JSON response
 		response := struct {
-			Score int `json:"score"`
+			Score int    `json:"score"`
+			Token string `json:"token,omitempty"`
 		}{
 			Score: score,
 		}
+		if stateless {
+			response.Token = target.Token()
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// RollsHandler handles the "POST /rolls" endpoint, which rolls a whole
+// sequence of balls from {"pins": [...]} at once. If any ball is invalid the
+// whole sequence is rejected, as it is if the client goes away before the
+// sequence is rolled.
+func RollsHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		var rolls struct {
+			Pins []int `json:"pins"`
+		}
+		if !requireJSON(w, r) {
+			return
+		}
+		if err := decodeBody(w, r, &rolls); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+			return
+		}
+		if r.Context().Err() != nil {
+			return
+		}
+
+		if err := gm.RollAll(rolls.Pins); err != nil {
+			writeGameError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+
+		response := struct {
+			Score  int   `json:"score"`
+			Frames []int `json:"frames"`
+		}{
+			Score:  gm.Score(),
+			Frames: gm.FrameScores(),
+		}
 		json.NewEncoder(w).Encode(response)
 	}
 }
 
-// ScoreHandler handles the "GET /score" endpoint.
+// ScoreHandler handles the "GET /score" endpoint. Passing the average, base
+// and pct query parameters adds a league handicap to the score. Responses
+// carry a weak ETag, and a request whose If-None-Match still matches the
+// game's state gets 304 Not Modified. Browsers asking for text/html get the
+// HTML scorecard instead of JSON, under an ETag of its own.
 func ScoreHandler(gm *Game) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
-			http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
 			return
 		}
 
-		score := gm.Score()
+		query := r.URL.Query()
+		withHandicap := query.Has("average") || query.Has("base") || query.Has("pct")
+		var average, base int
+		var pct float64
+		if withHandicap {
+			var errAverage, errBase, errPct error
+			average, errAverage = strconv.Atoi(query.Get("average"))
+			base, errBase = strconv.Atoi(query.Get("base"))
+			pct, errPct = strconv.ParseFloat(query.Get("pct"), 64)
+			if errAverage != nil || errBase != nil || errPct != nil || average < 0 || base < 0 || pct < 0 {
+				writeJSONError(w, http.StatusBadRequest, "invalid_query", "average, base and pct must be non-negative numbers")
+				return
+			}
+		}
+
+		_, span := startSpan(r, "score")
+		etag, score := gm.ETag()
+		span.SetAttributes(attrGameID.String(gm.ID()), attrScore.Int(score))
+		endSpan(span, nil)
+		html := negotiate(r, "application/json", "text/html") == "text/html"
+		if html {
+			etag = variantETag(etag, "html")
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Vary", "Accept")
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		if html {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			gm.WriteScoreCardHTML(w)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		if withHandicap {
+			h := handicap(average, base, pct)
+			response := struct {
+				Name     string `json:"name,omitempty"`
+				Score    int    `json:"score"`
+				Scratch  int    `json:"scratch"`
+				Handicap int    `json:"handicap"`
+			}{
+				Name:     gm.Name(),
+				Score:    score + h,
+				Scratch:  score,
+				Handicap: h,
+			}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
 
 		//Convert the score to a JSON response
 		response := struct {
-			Score int `json:"score"`
+			Name  string `json:"name,omitempty"`
+			Score int    `json:"score"`
 		}{
+			Name:  gm.Name(),
 			Score: score,
 		}
 		json.NewEncoder(w).Encode(response)
 	}
 }
 
+// etagMatches reports whether an If-None-Match header matches etag, using
+// the weak comparison RFC 9110 prescribes for If-None-Match.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// PreviewHandler handles the "POST /preview" endpoint, which reports the score
+// and frame breakdown the game would have if the ball in {"pins": N} were
+// rolled, without rolling it.
+func PreviewHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		var roll struct {
+			Pins int `json:"pins"`
+		}
+		if !requireJSON(w, r) {
+			return
+		}
+		if err := decodeBody(w, r, &roll); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+			return
+		}
+
+		// The clone isn't shared, so it can be rolled without its lock.
+		preview := gm.Clone()
+		if err := preview.roll(roll.Pins); err != nil {
+			writeGameError(w, err)
+			return
+		}
+
+		response := struct {
+			Score  int   `json:"score"`
+			Frames []int `json:"frames"`
+		}{
+			Score:  preview.score(),
+			Frames: preview.frameScores(),
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// UndoHandler handles the "POST /undo" endpoint.
+func UndoHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		if err := gm.Undo(); err != nil {
+			writeGameError(w, err)
+			return
+		}
+
+		response := struct {
+			Score int `json:"score"`
+		}{
+			Score: gm.Score(),
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// ResetFrameHandler handles the "POST /frames/current/reset" endpoint.
+func ResetFrameHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		if err := gm.ResetFrame(); err != nil {
+			writeGameError(w, err)
+			return
+		}
+
+		response := struct {
+			Score int `json:"score"`
+		}{
+			Score: gm.Score(),
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// EditRollHandler handles the "PATCH /rolls/{index}" endpoint, which corrects
+// the roll at the zero-based index to the pins in {"pins": N}.
+func EditRollHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+		index, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/rolls/"))
+		if err != nil {
+			writeJSONError(w, http.StatusNotFound, "not_found", "Not found")
+			return
+		}
+
+		var roll struct {
+			Pins int `json:"pins"`
+		}
+		if !requireJSON(w, r) {
+			return
+		}
+		if err := decodeBody(w, r, &roll); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+			return
+		}
+
+		if err := gm.EditRoll(index, roll.Pins); err != nil {
+			writeGameError(w, err)
+			return
+		}
+
+		response := struct {
+			Score  int   `json:"score"`
+			Frames []int `json:"frames"`
+		}{
+			Score:  gm.Score(),
+			Frames: gm.FrameScores(),
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// TargetHandler handles the "GET /target?score=N" endpoint, reporting how
+// many more points are needed to reach a score of N and whether it can still
+// be reached.
+func TargetHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+		target, err := strconv.Atoi(r.URL.Query().Get("score"))
+		if err != nil || target < 0 {
+			writeJSONError(w, http.StatusBadRequest, "invalid_query", "score must be a non-negative integer")
+			return
+		}
+
+		needed, possible := gm.PinsNeededFor(target)
+		response := struct {
+			Needed   int  `json:"needed"`
+			Possible bool `json:"possible"`
+		}{
+			Needed:   needed,
+			Possible: possible,
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// NextHandler handles the "GET /next" endpoint, reporting the legal pin
+// range of the next ball.
+func NextHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		response := struct {
+			MaxPins int `json:"max_pins"`
+		}{
+			MaxPins: gm.NextRollMax(),
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// PinsHandler handles the "GET /pins" endpoint.
+func PinsHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		response := struct {
+			Standing int `json:"standing"`
+		}{
+			Standing: gm.StandingPins(),
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// FramesHandler handles the "GET /frames" endpoint.
+func FramesHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		response := struct {
+			Frames []int `json:"frames"`
+		}{
+			Frames: gm.FrameScores(),
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// GameStateHandler handles the "GET /game" endpoint.
+func GameStateHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		response := struct {
+			Rolls []int `json:"rolls"`
+			Score int   `json:"score"`
+		}{
+			Rolls: gm.Rolls(),
+			Score: gm.Score(),
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// FinishHandler handles the "POST /game/finish" endpoint, which fills the
+// rest of the game with gutter balls and returns the final score.
+func FinishHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		response := struct {
+			Score int `json:"score"`
+		}{
+			Score: gm.Finish(),
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// ReplayHandler handles the "GET /replay" endpoint.
+func ReplayHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		json.NewEncoder(w).Encode(gm.Replay())
+	}
+}
+
+// ResetHandler handles the "DELETE /game" endpoint.
+func ResetHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		gm.Reset()
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// byMethod routes a request to the handler registered for its method and
+// rejects every other method, listing the registered ones in the Allow header.
+func byMethod(handlers map[string]http.HandlerFunc) http.HandlerFunc {
+	methods := make([]string, 0, len(handlers))
+	for method := range handlers {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return allowMethods(func(w http.ResponseWriter, r *http.Request) {
+		handlers[r.Method](w, r)
+	}, methods...)
+}
+
+// allowMethods passes requests made with one of methods on to h, and answers
+// any other with 405 Method Not Allowed and an Allow header listing methods.
+func allowMethods(h http.HandlerFunc, methods ...string) http.HandlerFunc {
+	allow := strings.Join(methods, ", ")
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, method := range methods {
+			if r.Method == method {
+				h(w, r)
+				return
+			}
+		}
+		w.Header().Set("Allow", allow)
+		writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+	}
+}
+
+// ErrDuplicateRoute is returned when a path is registered twice.
+var ErrDuplicateRoute = errors.New("route already registered")
+
+// router registers routes on its own ServeMux. Unlike the ServeMux it
+// doesn't panic when a path is registered twice, but keeps the first
+// registration and records ErrDuplicateRoute in err.
+type router struct {
+	mux   *http.ServeMux
+	paths map[string]bool
+	err   error
+}
+
+// newRouter allocates a router with an empty ServeMux.
+func newRouter() *router {
+	return &router{mux: http.NewServeMux(), paths: make(map[string]bool)}
+}
+
+// handle registers h for path, accepting only the given methods.
+func (rt *router) handle(path string, h http.HandlerFunc, methods ...string) {
+	rt.register(path, allowMethods(h, methods...))
+}
+
+// register registers h for path as it is.
+func (rt *router) register(path string, h http.HandlerFunc) {
+	if rt.paths[path] {
+		if rt.err == nil {
+			rt.err = fmt.Errorf("%w: %s", ErrDuplicateRoute, path)
+		}
+		return
+	}
+	rt.paths[path] = true
+	rt.mux.HandleFunc(path, h)
+}
+
+// registerRoutes registers every endpoint the server offers on rt: the
+// single-game endpoints on gm, the multi-game endpoints on store, the match
+// endpoints on match, and the health checks reporting ready.
+func registerRoutes(rt *router, gm *Game, store *GameStore, match *Match, ready *atomic.Bool) {
+	rt.handle("/healthz", HealthHandler(), http.MethodGet)
+	rt.handle("/readyz", ReadyHandler(ready), http.MethodGet)
+	rt.handle("/metrics", promhttp.Handler().ServeHTTP, http.MethodGet)
+	rt.register("/", notFound)
+
+	// The single-game endpoints predate the game store and are kept for
+	// backward compatibility; they operate on one shared game.
+	rt.handle("/roll", RollHandler(gm), http.MethodPost)
+	rt.handle("/score", ScoreHandler(gm), http.MethodGet)
+	rt.handle("/rolls", RollsHandler(gm), http.MethodPost)
+	rt.handle("/rolls/", EditRollHandler(gm), http.MethodPatch)
+	rt.handle("/preview", PreviewHandler(gm), http.MethodPost)
+	rt.handle("/undo", UndoHandler(gm), http.MethodPost)
+	rt.handle("/frames", FramesHandler(gm), http.MethodGet)
+	rt.handle("/frames/", FrameHandler(gm), http.MethodGet)
+	rt.handle("/frames/detail", FrameDetailsHandler(gm), http.MethodGet)
+	rt.handle("/frames/current/reset", ResetFrameHandler(gm), http.MethodPost)
+	rt.handle("/scorecard", ScoreCardHandler(gm), http.MethodGet)
+	rt.handle("/notation", NotationHandler(gm), http.MethodGet)
+	rt.handle("/stats", StatsHandler(gm), http.MethodGet)
+	rt.handle("/bonuses", BonusesHandler(gm), http.MethodGet)
+	rt.handle("/next", NextHandler(gm), http.MethodGet)
+	rt.handle("/pins", PinsHandler(gm), http.MethodGet)
+	rt.handle("/target", TargetHandler(gm), http.MethodGet)
+	rt.handle("/history", HistoryHandler(gm), http.MethodGet)
+	rt.handle("/replay", ReplayHandler(gm), http.MethodGet)
+	rt.handle("/simulate", SimulateHandler(), http.MethodPost)
+	rt.handle("/events", EventsHandler(gm), http.MethodGet)
+	rt.handle("/ws", WebSocketHandler(gm), http.MethodGet)
+	rt.register("/game", byMethod(map[string]http.HandlerFunc{
+		http.MethodGet:    GameStateHandler(gm),
+		http.MethodDelete: ResetHandler(gm),
+	}))
+	rt.handle("/game/finish", FinishHandler(gm), http.MethodPost)
+
+	rt.handle("/games", GamesHandler(store), http.MethodGet, http.MethodPost)
+	rt.handle("/games/", GameHandler(store), http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete)
+	rt.handle("/series", CreateSeriesHandler(store), http.MethodPost)
+	rt.handle("/series/", SeriesHandler(store), http.MethodGet, http.MethodPost)
+	rt.handle("/leaderboard", LeaderboardHandler(store), http.MethodGet)
+	rt.handle("/players/", PlayersHandler(store), http.MethodGet)
+	rt.handle("/compare", CompareHandler(store), http.MethodGet)
+	rt.handle("/compare/odds", OddsHandler(store), http.MethodGet)
+
+	rt.handle("/match", MatchHandler(match), http.MethodGet, http.MethodPost)
+	rt.handle("/match/roll", MatchRollHandler(match), http.MethodPost)
+}
+
+// notFound answers requests for unknown paths with a JSON 404.
+func notFound(w http.ResponseWriter, r *http.Request) {
+	writeJSONError(w, http.StatusNotFound, "not_found", "Not found")
+}
+
+// errorCodes maps the errors reported by games to the HTTP status and error
+// code sent to clients.
+var errorCodes = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{ErrInvalidPinCount, http.StatusBadRequest, "invalid_pin_count"},
+	{ErrFrameOverfill, http.StatusBadRequest, "frame_overfill"},
+	{ErrInvalidGameState, http.StatusBadRequest, "invalid_game_state"},
+	{ErrInvalidNotation, http.StatusBadRequest, "invalid_notation"},
+	{ErrInvalidMark, http.StatusBadRequest, "invalid_mark"},
+	{ErrMisplacedMark, http.StatusBadRequest, "misplaced_mark"},
+	{ErrInvalidToken, http.StatusBadRequest, "invalid_token"},
+	{ErrInvalidFrame, http.StatusBadRequest, "invalid_frame"},
+	{ErrInvalidName, http.StatusBadRequest, "invalid_name"},
+	{ErrGameOver, http.StatusConflict, "game_over"},
+	{ErrNothingToUndo, http.StatusConflict, "nothing_to_undo"},
+	{ErrPositionMismatch, http.StatusConflict, "position_mismatch"},
+	{ErrNoPlayers, http.StatusConflict, "no_players"},
+	{ErrGameStarted, http.StatusConflict, "game_started"},
+	{ErrRollNotFound, http.StatusNotFound, "roll_not_found"},
+	{ErrFrameNotBowled, http.StatusNotFound, "frame_not_bowled"},
+	{ErrGameNotFound, http.StatusNotFound, "game_not_found"},
+	{ErrSeriesNotFound, http.StatusNotFound, "series_not_found"},
+	{ErrTooManyGames, http.StatusTooManyRequests, "too_many_games"},
+	{ErrIDsExhausted, http.StatusInternalServerError, "ids_exhausted"},
+}
+
+// writeGameError writes err as a JSON error envelope, with the status and
+// code of the error it wraps.
+func writeGameError(w http.ResponseWriter, err error) {
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.err) {
+			body := apiError{Code: ec.code, Message: err.Error()}
+			var pos *PositionError
+			if errors.As(err, &pos) {
+				body.Frame, body.Ball = pos.Frame, pos.Ball
+			}
+			writeAPIError(w, ec.status, body)
+			return
+		}
+	}
+	slog.Error("unexpected game error", "error", err)
+	writeJSONError(w, http.StatusInternalServerError, "internal", err.Error())
+}
+
+// decodeBody decodes the JSON value in r's body into v. Bodies larger than
+// maxBodyBytes are rejected, as is anything but whitespace after the value.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		return errors.New("unexpected data after the request body")
+	}
+	return nil
+}
+
+// requireJSON checks that a request body is declared as JSON, writing a 415
+// error and returning false if it is declared as anything else. A body
+// without a Content-Type is taken to be JSON.
+func requireJSON(w http.ResponseWriter, r *http.Request) bool {
+	switch mediaType(r) {
+	case "", "application/json":
+		return true
+	}
+	writeJSONError(w, http.StatusUnsupportedMediaType, "unsupported_media_type", "Content-Type must be application/json")
+	return false
+}
+
+// errUnsupportedMediaType is returned when a request body is sent with a
+// Content-Type the handler can't parse.
+var errUnsupportedMediaType = errors.New("unsupported media type")
+
+// rollRequest is the body of a single roll: the pins knocked down, or a
+// mark as RollMark takes it, and optionally where the ball is rolled.
+type rollRequest struct {
+	Pins *int   `json:"pins"`
+	Mark string `json:"mark"`
+
+	// Frame and Ball are the position the client believes the ball is
+	// rolled at, or 0 if it doesn't say.
+	Frame int `json:"frame"`
+	Ball  int `json:"ball"`
+}
+
+// decodeRoll parses the body of r into roll, as JSON or, if its Content-Type
+// says so, as a form. A body without a Content-Type is taken to be JSON, and
+// any other type returns errUnsupportedMediaType. A form with neither pins
+// nor mark, such as a JSON body sent as a form, leaves both unset.
+func decodeRoll(w http.ResponseWriter, r *http.Request, roll *rollRequest) error {
+	switch mediaType(r) {
+	case "", "application/json":
+		return decodeBody(w, r, roll)
+	case "application/x-www-form-urlencoded":
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+		if err := r.ParseForm(); err != nil {
+			return err
+		}
+		if s := r.PostForm.Get("pins"); s != "" {
+			pins, err := strconv.Atoi(s)
+			if err != nil {
+				return err
+			}
+			roll.Pins = &pins
+		}
+		roll.Mark = r.PostForm.Get("mark")
+		for _, field := range []struct {
+			name string
+			n    *int
+		}{{"frame", &roll.Frame}, {"ball", &roll.Ball}} {
+			if s := r.PostForm.Get(field.name); s != "" {
+				n, err := strconv.Atoi(s)
+				if err != nil {
+					return err
+				}
+				*field.n = n
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedMediaType, r.Header.Get("Content-Type"))
+	}
+}
+
+// mediaType returns the media type of the request body, without parameters
+// such as charset, or "" if it has no Content-Type.
+func mediaType(r *http.Request) string {
+	header := r.Header.Get("Content-Type")
+	if header == "" {
+		return ""
+	}
+	mt, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return header
+	}
+	return mt
+}
+
+// negotiate returns whichever of offers the request's Accept header prefers,
+// or the first offer if it accepts none of them or has no Accept header.
+// Media ranges such as text/* and */* match any offer they cover.
+func negotiate(r *http.Request, offers ...string) string {
+	best, bestQ, bestExact := offers[0], 0.0, false
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaRange, params, _ := strings.Cut(accepted, ";")
+		mediaRange = strings.TrimSpace(mediaRange)
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if name, value, _ := strings.Cut(strings.TrimSpace(param), "="); name == "q" {
+				q, _ = strconv.ParseFloat(value, 64)
+			}
+		}
+		for _, offer := range offers {
+			kind, _, _ := strings.Cut(offer, "/")
+			exact := mediaRange == offer
+			if !exact && mediaRange != kind+"/*" && mediaRange != "*/*" {
+				continue
+			}
+			// An exact match outranks a wildcard of the same quality.
+			if q > bestQ || q == bestQ && q > 0 && exact && !bestExact {
+				best, bestQ, bestExact = offer, q, exact
+			}
+		}
+	}
+	return best
+}
+
+// writeJSONError writes a {"error": {"code": ..., "message": ...}} envelope
+// with the given status.
+func writeJSONError(w http.ResponseWriter, status int, code, message string) {
+	writeAPIError(w, status, apiError{Code: code, Message: message})
+}
+
+// apiError is the body of the JSON error envelope. A position mismatch also
+// carries the frame and ball the game is at.
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Frame   int    `json:"frame,omitempty"`
+	Ball    int    `json:"ball,omitempty"`
+}
+
+// writeAPIError writes body as a JSON error envelope with the given status.
+func writeAPIError(w http.ResponseWriter, status int, body apiError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	response := struct {
+		Error apiError `json:"error"`
+	}{
+		Error: body,
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// resolveAddr picks the listen address. An explicitly set -addr flag wins,
+// followed by the BOWLING_ADDR environment variable, then defaultAddr.
+func resolveAddr(flagAddr string, flagSet bool, lookupEnv func(string) (string, bool)) string {
+	if flagSet {
+		return flagAddr
+	}
+	if addr, ok := lookupEnv(addrEnv); ok && addr != "" {
+		return addr
+	}
+	return defaultAddr
+}
+
+// isFlagSet reports whether the named flag was given on the command line.
+func isFlagSet(name string) (set bool) {
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// serveUntilSignal serves on ln until a signal arrives on stop, then shuts
+// server down, giving in-flight requests up to timeout to finish. cleanup, if
+// not nil, is called once the server has stopped, e.g. to flush game state.
+func serveUntilSignal(server *http.Server, ln net.Listener, stop <-chan os.Signal, timeout time.Duration, cleanup func() error) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(ln)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case sig := <-stop:
+		slog.Info("shutting down", "signal", sig)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	err := server.Shutdown(ctx)
+	if cleanup != nil {
+		if cleanupErr := cleanup(); err == nil {
+			err = cleanupErr
+		}
+	}
+	return err
+}
+
 func main() {
+	addrFlag := flag.String("addr", defaultAddr, "address to listen on (overrides $"+addrEnv+")")
+	statePath := flag.String("state", "", "file the shared game is loaded from and saved to on shutdown")
+	grpcAddr := flag.String("grpc-addr", defaultGRPCAddr, "address the gRPC server listens on, or empty to disable it")
+	corsOrigin := flag.String("cors-origin", "*", "origin browser clients may call the API from")
+	logLevel := flag.String("log-level", "info", "minimum level logged: debug, info, warn or error")
+	webhookURL := flag.String("webhook-url", "", "URL each game created through /games is POSTed to once complete")
+	gameTTL := flag.Duration("game-ttl", 0, "how long a game created through /games may sit idle before it is evicted, or 0 to keep games forever")
+	maxGames := flag.Int("max-games", 0, "most games /games may hold at once, or 0 for no limit")
+	rate := flag.Float64("rate", 0, "requests a second each client IP may make on average, or 0 for no limit")
+	burst := flag.Int("burst", 10, "requests each client IP may make in a burst when -rate is set")
+	trustProxy := flag.Bool("trust-proxy", false, "rate limit clients by X-Forwarded-For instead of their remote address")
+	flag.Parse()
+	level, err := parseLogLevel(*logLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -log-level %q: %v\n", *logLevel, err)
+		os.Exit(2)
+	}
+	logger := newLogger(os.Stderr, level)
+	slog.SetDefault(logger)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	addr := resolveAddr(*addrFlag, isFlagSet("addr"), os.LookupEnv)
+
+	ready := new(atomic.Bool)
 	gm := NewGame()
-	http.HandleFunc("/roll", RollHandler(gm))
-	http.HandleFunc("/score", ScoreHandler(gm))
-	http.ListenAndServe(":8080", nil)
+	var cleanup func() error
+	if *statePath != "" {
+		loaded, err := LoadGame(*statePath)
+		switch {
+		case err == nil:
+			gm = loaded
+		case !errors.Is(err, ErrNoSavedGame):
+			fatal("loading game", err)
+		}
+		cleanup = func() error {
+			return SaveGame(*statePath, gm)
+		}
+	}
+
+	store := NewGameStore()
+	store.SetMaxGames(*maxGames)
+	if *webhookURL != "" {
+		store.OnComplete(NewWebhook(*webhookURL, webhookAttempts, webhookBackoff).Notify)
+	}
+	if *gameTTL > 0 {
+		stopSweeper := store.StartSweeper(*gameTTL, *gameTTL/2)
+		saveGame := cleanup
+		cleanup = func() error {
+			stopSweeper()
+			if saveGame != nil {
+				return saveGame()
+			}
+			return nil
+		}
+	}
+	rt := newRouter()
+	registerRoutes(rt, gm, store, NewMatch(), ready)
+	if rt.err != nil {
+		fatal("registering routes", rt.err)
+	}
+
+	if *grpcAddr != "" {
+		grpcLn, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			fatal("listening for gRPC", err)
+		}
+		grpcServer := NewGRPCServer(store)
+		go grpcServer.Serve(grpcLn)
+		slog.Info("gRPC listening", "addr", *grpcAddr)
+
+		saveGame := cleanup
+		cleanup = func() error {
+			grpcServer.GracefulStop()
+			if saveGame != nil {
+				return saveGame()
+			}
+			return nil
+		}
+	}
+
+	var handler http.Handler = rt.mux
+	if *rate > 0 {
+		if *burst < 1 {
+			fatal("rate limiting", fmt.Errorf("-burst must be at least 1, not %d", *burst))
+		}
+		handler = NewRateLimiter(*rate, *burst, *trustProxy).Limit(handler)
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		fatal("listening", err)
+	}
+	slog.Info("listening", "addr", addr)
+	ready.Store(true)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	server := &http.Server{
+		Handler:  withMiddleware(*corsOrigin, handler),
+		ErrorLog: serverErrorLog(logger),
+	}
+	if err := serveUntilSignal(server, ln, stop, shutdownTimeout, cleanup); err != nil {
+		fatal("serving", err)
+	}
 }
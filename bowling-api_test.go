@@ -1,6 +1,21 @@
 package main
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
 
 func TestGutterBalls(t *testing.T) {
 	t.Log("Rolling all gutter balls... (expected score: 0)")
@@ -56,3 +71,2037 @@ func TestPerfectGame(t *testing.T) {
 		t.Errorf("Expected score of 300, but it was %d instead.", score)
 	}
 }
+
+func TestInvalidPinCount(t *testing.T) {
+	t.Log("Rolling 11 pins... (expected: rejected, game unchanged)")
+	game := NewGame()
+	game.Roll(4)
+
+	if err := game.Roll(11); !errors.Is(err, ErrInvalidPinCount) {
+		t.Errorf("Expected ErrInvalidPinCount, but it was %v instead.", err)
+	}
+	if game.current != 1 {
+		t.Errorf("Expected current of 1, but it was %d instead.", game.current)
+	}
+	if score := game.Score(); score != 4 {
+		t.Errorf("Expected score of 4, but it was %d instead.", score)
+	}
+}
+
+func TestRollHandlerRejectsInvalidPinCount(t *testing.T) {
+	t.Log("POSTing a roll of -3 pins... (expected status: 400)")
+	game := NewGame()
+	req := httptest.NewRequest(http.MethodPost, "/roll", strings.NewReader(`{"pins": -3}`))
+	rec := httptest.NewRecorder()
+	RollHandler(game)(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status of 400, but it was %d instead.", rec.Code)
+	}
+	if code := decodeErrorCode(t, rec); code != "invalid_pin_count" {
+		t.Errorf("Expected error code invalid_pin_count, but it was %q instead.", code)
+	}
+	if game.current != 0 {
+		t.Errorf("Expected current of 0, but it was %d instead.", game.current)
+	}
+}
+
+func TestRollingPastEndOfGame(t *testing.T) {
+	t.Log("Rolling 30 strikes... (expected: no panic, rolls after the twelfth rejected)")
+	game := NewGame()
+	for x := 0; x < 30; x++ {
+		err := game.Roll(10)
+		if x < 12 && err != nil {
+			t.Fatalf("Expected roll %d to succeed, but it failed with %v.", x+1, err)
+		}
+		if x >= 12 && !errors.Is(err, ErrGameOver) {
+			t.Errorf("Expected roll %d to fail with ErrGameOver, but it was %v instead.", x+1, err)
+		}
+	}
+
+	if score := game.Score(); score != 300 {
+		t.Errorf("Expected score of 300, but it was %d instead.", score)
+	}
+}
+
+func TestIsCompleteTenthFrame(t *testing.T) {
+	tests := []struct {
+		name  string
+		tenth []int
+		want  []bool
+	}{
+		{"strike needs two fill balls", []int{10, 3, 4}, []bool{false, false, true}},
+		{"spare needs one fill ball", []int{6, 4, 7}, []bool{false, false, true}},
+		{"open frame ends after two balls", []int{6, 3}, []bool{false, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := NewGame()
+			game.rollMany(18, 0)
+			if game.IsComplete() {
+				t.Fatal("Expected game to be incomplete before the tenth frame.")
+			}
+			for x, pins := range tt.tenth {
+				game.Roll(pins)
+				if got := game.IsComplete(); got != tt.want[x] {
+					t.Errorf("After tenth-frame ball %d expected IsComplete of %v, but it was %v instead.", x+1, tt.want[x], got)
+				}
+			}
+		})
+	}
+}
+
+func TestConcurrentRollsAndScores(t *testing.T) {
+	t.Log("Rolling one pin 50 times while reading the score concurrently... (expected score: 20)")
+	game := NewGame()
+	var wg sync.WaitGroup
+	for x := 0; x < 50; x++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			game.Roll(1)
+		}()
+		go func() {
+			defer wg.Done()
+			game.Score()
+		}()
+	}
+	wg.Wait()
+
+	if !game.IsComplete() {
+		t.Error("Expected game to be complete, but it was not.")
+	}
+	if score := game.Score(); score != 20 {
+		t.Errorf("Expected score of 20, but it was %d instead.", score)
+	}
+}
+
+func TestReset(t *testing.T) {
+	t.Log("Bowling a perfect game, resetting, then all gutters... (expected score: 0)")
+	game := NewGame()
+	game.rollMany(12, 10)
+	if score := game.Score(); score != 300 {
+		t.Fatalf("Expected score of 300, but it was %d instead.", score)
+	}
+
+	game.Reset()
+	game.rollMany(20, 0)
+
+	if score := game.Score(); score != 0 {
+		t.Errorf("Expected score of 0, but it was %d instead.", score)
+	}
+	if !game.IsComplete() {
+		t.Error("Expected game to be complete, but it was not.")
+	}
+}
+
+func TestResetHandler(t *testing.T) {
+	t.Log("DELETEing the game after a roll... (expected status: 204, score: 0)")
+	game := NewGame()
+	game.Roll(8)
+	rec := httptest.NewRecorder()
+	ResetHandler(game)(rec, httptest.NewRequest(http.MethodDelete, "/game", nil))
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status of 204, but it was %d instead.", rec.Code)
+	}
+	if score := game.Score(); score != 0 {
+		t.Errorf("Expected score of 0, but it was %d instead.", score)
+	}
+}
+
+func TestFrameScoresPerfectGame(t *testing.T) {
+	t.Log("Rolling all strikes... (expected frames: 30, 60, ... 300)")
+	game := NewGame()
+	game.rollMany(12, 10)
+
+	want := []int{30, 60, 90, 120, 150, 180, 210, 240, 270, 300}
+	if frames := game.FrameScores(); !reflect.DeepEqual(frames, want) {
+		t.Errorf("Expected frames of %v, but they were %v instead.", want, frames)
+	}
+}
+
+func TestFrameScoresMixedGame(t *testing.T) {
+	t.Log("Rolling X 7/ 9- X -8 8/ -6 X X X81... (expected score: 167)")
+	game := NewGame()
+	game.rollMixedGame()
+
+	want := []int{20, 39, 48, 66, 74, 84, 90, 120, 148, 167}
+	if frames := game.FrameScores(); !reflect.DeepEqual(frames, want) {
+		t.Errorf("Expected frames of %v, but they were %v instead.", want, frames)
+	}
+}
+
+func TestFrameScoresOmitsPendingBonus(t *testing.T) {
+	t.Log("Rolling a strike, then a 7... (expected frames: none)")
+	game := NewGame()
+	game.rollStrike()
+	game.Roll(7)
+
+	if frames := game.FrameScores(); len(frames) != 0 {
+		t.Errorf("Expected no frames, but they were %v instead.", frames)
+	}
+}
+
+func TestFramesHandler(t *testing.T) {
+	t.Log("GETting the frames of a spare and a 3... (expected frames: 13, 16)")
+	game := NewGame()
+	game.rollSpare()
+	game.Roll(3)
+	game.Roll(0)
+	rec := httptest.NewRecorder()
+	FramesHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/frames", nil))
+
+	var got struct {
+		Frames []int `json:"frames"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if want := []int{13, 16}; !reflect.DeepEqual(got.Frames, want) {
+		t.Errorf("Expected frames of %v, but they were %v instead.", want, got.Frames)
+	}
+}
+
+func TestRollsReturnsCopy(t *testing.T) {
+	t.Log("Mutating the slice returned by Rolls... (expected: game unchanged)")
+	game := NewGame()
+	game.Roll(3)
+	game.Roll(4)
+
+	rolls := game.Rolls()
+	if want := []int{3, 4}; !reflect.DeepEqual(rolls, want) {
+		t.Fatalf("Expected rolls of %v, but they were %v instead.", want, rolls)
+	}
+	rolls[0] = 10
+
+	if game.rolls[0] != 3 {
+		t.Errorf("Expected first roll of 3, but it was %d instead.", game.rolls[0])
+	}
+	if score := game.Score(); score != 7 {
+		t.Errorf("Expected score of 7, but it was %d instead.", score)
+	}
+}
+
+func TestGameStateHandler(t *testing.T) {
+	t.Log("GETting the game after a 3 and a 4... (expected rolls: 3, 4, score: 7)")
+	game := NewGame()
+	game.Roll(3)
+	game.Roll(4)
+	rec := httptest.NewRecorder()
+	GameStateHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/game", nil))
+
+	var got struct {
+		Rolls []int `json:"rolls"`
+		Score int   `json:"score"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if want := []int{3, 4}; !reflect.DeepEqual(got.Rolls, want) {
+		t.Errorf("Expected rolls of %v, but they were %v instead.", want, got.Rolls)
+	}
+	if got.Score != 7 {
+		t.Errorf("Expected score of 7, but it was %d instead.", got.Score)
+	}
+}
+
+func TestUndo(t *testing.T) {
+	t.Log("Rolling a strike, undoing it, then rolling a spare and a 3... (expected score: 16)")
+	game := NewGame()
+	game.rollStrike()
+	if err := game.Undo(); err != nil {
+		t.Fatalf("Expected undo to succeed, but it failed with %v.", err)
+	}
+	game.rollSpare()
+	game.Roll(3)
+	game.rollMany(17, 0)
+
+	want := NewGame()
+	want.rollSpare()
+	want.Roll(3)
+	want.rollMany(17, 0)
+	if score, wanted := game.Score(), want.Score(); score != wanted {
+		t.Errorf("Expected score of %d, but it was %d instead.", wanted, score)
+	}
+	if !game.IsComplete() {
+		t.Error("Expected game to be complete, but it was not.")
+	}
+}
+
+func TestUndoWithoutRolls(t *testing.T) {
+	t.Log("Undoing on a fresh game... (expected: ErrNothingToUndo)")
+	game := NewGame()
+
+	if err := game.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Expected ErrNothingToUndo, but it was %v instead.", err)
+	}
+}
+
+func TestResetFrame(t *testing.T) {
+	tests := []struct {
+		name  string
+		rolls []int
+		want  []int
+		err   error
+	}{
+		{"one ball into a frame", []int{10, 7, 3, 4}, []int{10, 7, 3}, nil},
+		{"tenth frame fill ball", []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 3}, []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, nil},
+		{"frame not started", []int{10, 7, 3}, []int{10, 7, 3}, ErrNothingToUndo},
+		{"complete game", []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3, 4}, []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3, 4}, ErrGameOver},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Resetting the frame in progress after %v... (expected rolls %v, error %v)", tt.rolls, tt.want, tt.err)
+			game := NewGame()
+			game.RollAll(tt.rolls)
+
+			if err := game.ResetFrame(); !errors.Is(err, tt.err) {
+				t.Errorf("Expected error %v, but it was %v instead.", tt.err, err)
+			}
+			if rolls := game.Rolls(); !reflect.DeepEqual(rolls, tt.want) {
+				t.Errorf("Expected rolls of %v, but they were %v instead.", tt.want, rolls)
+			}
+		})
+	}
+}
+
+func TestResetFrameHandler(t *testing.T) {
+	t.Log("POSTing a frame reset after X 7/ 4... (expected score: 30, frames 1 and 2 intact)")
+	game := NewGame()
+	game.RollAll([]int{10, 7, 3, 4})
+	rec := httptest.NewRecorder()
+	ResetFrameHandler(game)(rec, httptest.NewRequest(http.MethodPost, "/frames/current/reset", nil))
+
+	var got struct {
+		Score int `json:"score"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Score != 30 {
+		t.Errorf("Expected score of 30, but it was %d instead.", got.Score)
+	}
+	if frames := game.FrameDetails(); len(frames) != 2 || !reflect.DeepEqual(frames[1].Balls, []int{7, 3}) {
+		t.Errorf("Expected frames X and 7/ to be intact, but they were %+v.", frames)
+	}
+	if frame := game.CurrentFrame(); frame != 3 {
+		t.Errorf("Expected to be bowling frame 3, but it was frame %d.", frame)
+	}
+}
+
+func TestUndoHandler(t *testing.T) {
+	t.Log("POSTing an undo after a 5 and a 4... (expected score: 5)")
+	game := NewGame()
+	game.Roll(5)
+	game.Roll(4)
+	rec := httptest.NewRecorder()
+	UndoHandler(game)(rec, httptest.NewRequest(http.MethodPost, "/undo", nil))
+
+	var got struct {
+		Score int `json:"score"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Score != 5 {
+		t.Errorf("Expected score of 5, but it was %d instead.", got.Score)
+	}
+}
+
+func TestFrameOverfill(t *testing.T) {
+	t.Log("Rolling an 8, then a 5 in the same frame... (expected: rejected)")
+	game := NewGame()
+	game.Roll(8)
+
+	if err := game.Roll(5); !errors.Is(err, ErrFrameOverfill) {
+		t.Errorf("Expected ErrFrameOverfill, but it was %v instead.", err)
+	}
+	if score := game.Score(); score != 8 {
+		t.Errorf("Expected score of 8, but it was %d instead.", score)
+	}
+}
+
+func TestTenthFrameFreshPins(t *testing.T) {
+	t.Log("Rolling all gutters, then three strikes in the tenth... (expected score: 30)")
+	game := NewGame()
+	game.rollMany(18, 0)
+	for x := 0; x < 3; x++ {
+		if err := game.Roll(10); err != nil {
+			t.Fatalf("Expected tenth-frame ball %d to succeed, but it failed with %v.", x+1, err)
+		}
+	}
+
+	if score := game.Score(); score != 30 {
+		t.Errorf("Expected score of 30, but it was %d instead.", score)
+	}
+}
+
+func TestMaxPossibleScoreFreshGame(t *testing.T) {
+	t.Log("Asking a fresh game for its best possible score... (expected: 300)")
+	game := NewGame()
+
+	if max := game.MaxPossibleScore(); max != 300 {
+		t.Errorf("Expected max score of 300, but it was %d instead.", max)
+	}
+}
+
+func TestMaxPossibleScoreAfterGutter(t *testing.T) {
+	t.Log("Rolling a gutter ball first... (expected max score: 290)")
+	game := NewGame()
+	game.Roll(0)
+
+	if max := game.MaxPossibleScore(); max != 290 {
+		t.Errorf("Expected max score of 290, but it was %d instead.", max)
+	}
+	if game.current != 1 {
+		t.Errorf("Expected current of 1, but it was %d instead.", game.current)
+	}
+}
+
+func TestMaxPossibleScoreLockedOut(t *testing.T) {
+	t.Log("Rolling an open 3, 4 first frame... (expected max score: 277)")
+	game := NewGame()
+	game.Roll(3)
+	game.Roll(4)
+
+	if max := game.MaxPossibleScore(); max != 277 {
+		t.Errorf("Expected max score of 277, but it was %d instead.", max)
+	}
+}
+
+func TestCurrentFrameAndBall(t *testing.T) {
+	t.Log("Walking through X 3 4 X 5 ... and a strike-filled tenth, checking frame and ball before each roll")
+	game := NewGame()
+	steps := []struct {
+		pins, frame, ball int
+	}{
+		{10, 1, 1},
+		{3, 2, 1}, {4, 2, 2},
+		{10, 3, 1},
+		{5, 4, 1}, {2, 4, 2},
+		{0, 5, 1}, {0, 5, 2},
+		{0, 6, 1}, {0, 6, 2},
+		{0, 7, 1}, {0, 7, 2},
+		{10, 8, 1},
+		{10, 9, 1},
+		{10, 10, 1}, {6, 10, 2}, {1, 10, 3},
+	}
+	for x, step := range steps {
+		if frame := game.CurrentFrame(); frame != step.frame {
+			t.Errorf("Before roll %d expected frame %d, but it was %d instead.", x+1, step.frame, frame)
+		}
+		if ball := game.BallInFrame(); ball != step.ball {
+			t.Errorf("Before roll %d expected ball %d, but it was %d instead.", x+1, step.ball, ball)
+		}
+		game.Roll(step.pins)
+	}
+
+	if frame, ball := game.CurrentFrame(), game.BallInFrame(); frame != 10 || ball != 0 {
+		t.Errorf("Expected frame 10, ball 0 after the game, but it was frame %d, ball %d instead.", frame, ball)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	t.Log("Marshaling and unmarshaling X 7/ 9- X -8 8/ -6 X X X81... (expected score: 167)")
+	game := NewGame()
+	game.rollMixedGame()
+
+	data, err := json.Marshal(game)
+	if err != nil {
+		t.Fatalf("Expected marshal to succeed, but it failed with %v.", err)
+	}
+	restored := new(Game)
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("Expected unmarshal to succeed, but it failed with %v.", err)
+	}
+
+	if score, want := restored.Score(), game.Score(); score != want {
+		t.Errorf("Expected score of %d, but it was %d instead.", want, score)
+	}
+	if len(restored.rolls) != maxThrowsPerGame {
+		t.Errorf("Expected %d roll slots, but there were %d instead.", maxThrowsPerGame, len(restored.rolls))
+	}
+}
+
+func TestUnmarshalInconsistentState(t *testing.T) {
+	t.Log("Unmarshaling a game whose current disagrees with its rolls... (expected: ErrInvalidGameState)")
+	err := json.Unmarshal([]byte(`{"rolls":[1,2],"current":5}`), new(Game))
+
+	if !errors.Is(err, ErrInvalidGameState) {
+		t.Errorf("Expected ErrInvalidGameState, but it was %v instead.", err)
+	}
+}
+
+func TestResolveAddr(t *testing.T) {
+	env := func(addr string) func(string) (string, bool) {
+		return func(key string) (string, bool) {
+			if key != addrEnv || addr == "" {
+				return "", false
+			}
+			return addr, true
+		}
+	}
+	tests := []struct {
+		name     string
+		flagAddr string
+		flagSet  bool
+		envAddr  string
+		want     string
+	}{
+		{"flag wins over env", ":9000", true, ":9100", ":9000"},
+		{"env wins over default", defaultAddr, false, ":9100", ":9100"},
+		{"default when neither set", defaultAddr, false, "", defaultAddr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if addr := resolveAddr(tt.flagAddr, tt.flagSet, env(tt.envAddr)); addr != tt.want {
+				t.Errorf("Expected address of %q, but it was %q instead.", tt.want, addr)
+			}
+		})
+	}
+}
+
+func TestServeUntilSignal(t *testing.T) {
+	t.Log("Sending a fake interrupt to a running server... (expected: clean shutdown and flush)")
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected to listen, but got %v.", err)
+	}
+	game := NewGame()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/score", ScoreHandler(game))
+
+	stop := make(chan os.Signal, 1)
+	flushed := false
+	done := make(chan error, 1)
+	go func() {
+		done <- serveUntilSignal(&http.Server{Handler: mux}, ln, stop, time.Second, func() error {
+			flushed = true
+			return nil
+		})
+	}()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/score")
+	if err != nil {
+		t.Fatalf("Expected the server to answer, but got %v.", err)
+	}
+	resp.Body.Close()
+
+	stop <- os.Interrupt
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected a clean shutdown, but got %v.", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the server to shut down, but it kept running.")
+	}
+	if !flushed {
+		t.Error("Expected the game to be flushed on shutdown, but it was not.")
+	}
+	if _, err := http.Get("http://" + ln.Addr().String() + "/score"); err == nil {
+		t.Error("Expected the server to refuse connections after shutdown, but it answered.")
+	}
+}
+
+func TestScoreThroughPendingStrike(t *testing.T) {
+	t.Log("Rolling an open 3, 4, a strike, then a 5... (expected: 7 through 1 frame)")
+	game := NewGame()
+	game.Roll(3)
+	game.Roll(4)
+	game.rollStrike()
+	game.Roll(5)
+
+	score, frames := game.ScoreThrough()
+	if score != 7 || frames != 1 {
+		t.Errorf("Expected 7 through 1 frame, but it was %d through %d instead.", score, frames)
+	}
+}
+
+func TestScoreThroughCompleteGame(t *testing.T) {
+	t.Log("Rolling X 7/ 9- X -8 8/ -6 X X X81... (expected: 167 through 10 frames)")
+	game := NewGame()
+	game.rollMixedGame()
+
+	score, frames := game.ScoreThrough()
+	if score != 167 || frames != 10 {
+		t.Errorf("Expected 167 through 10 frames, but it was %d through %d instead.", score, frames)
+	}
+}
+
+func TestNoTapStrike(t *testing.T) {
+	t.Log("Rolling 9, 3, 4 in a 9-pin no-tap game and a standard one... (expected scores: 24 and 16)")
+	noTap := NewGame(WithNoTap(9))
+	noTap.Roll(9)
+	noTap.Roll(3)
+	noTap.Roll(4)
+	noTap.rollMany(16, 0)
+
+	standard := NewGame()
+	standard.Roll(9)
+	standard.Roll(0)
+	standard.Roll(3)
+	standard.Roll(4)
+	standard.rollMany(16, 0)
+
+	if score := noTap.Score(); score != 24 {
+		t.Errorf("Expected no-tap score of 24, but it was %d instead.", score)
+	}
+	if score := standard.Score(); score != 16 {
+		t.Errorf("Expected standard score of 16, but it was %d instead.", score)
+	}
+}
+
+func TestNoTapPerfectGame(t *testing.T) {
+	t.Log("Rolling twelve 9s in a 9-pin no-tap game... (expected score: 300)")
+	game := NewGame(WithNoTap(9))
+	game.rollMany(12, 9)
+
+	if score := game.Score(); score != 300 {
+		t.Errorf("Expected score of 300, but it was %d instead.", score)
+	}
+	if !game.IsComplete() {
+		t.Error("Expected game to be complete, but it was not.")
+	}
+}
+
+func TestNoTapSecondBall(t *testing.T) {
+	t.Log("Rolling 0 then 9 in a 9-pin no-tap game... (expected: an open frame of 9)")
+	game := NewGame(WithNoTap(9))
+	game.Roll(0)
+	game.Roll(9)
+
+	if frame := game.CurrentFrame(); frame != 2 {
+		t.Errorf("Expected frame 2, but it was %d instead.", frame)
+	}
+	if score := game.Score(); score != 9 {
+		t.Errorf("Expected score of 9, but it was %d instead.", score)
+	}
+}
+
+func TestScoreWithHandicap(t *testing.T) {
+	t.Log("Scoring a 167 game for a 180 average at 90% of 220... (expected score: 203)")
+	game := NewGame()
+	game.rollMixedGame()
+
+	if score := game.ScoreWithHandicap(180, 220, 0.9); score != 203 {
+		t.Errorf("Expected score of 203, but it was %d instead.", score)
+	}
+}
+
+func TestScoreWithHandicapClampedToZero(t *testing.T) {
+	t.Log("Scoring a 167 game for a 230 average at 90% of 220... (expected score: 167)")
+	game := NewGame()
+	game.rollMixedGame()
+
+	if score := game.ScoreWithHandicap(230, 220, 0.9); score != 167 {
+		t.Errorf("Expected score of 167, but it was %d instead.", score)
+	}
+}
+
+func TestScoreHandlerHandicap(t *testing.T) {
+	tests := []struct {
+		query    string
+		status   int
+		score    int
+		handicap int
+	}{
+		{"?average=180&base=220&pct=0.9", http.StatusOK, 203, 36},
+		{"?average=240&base=220&pct=0.9", http.StatusOK, 167, 0},
+		{"?average=190&base=220&pct=0.7", http.StatusOK, 188, 21},
+		{"?average=abc&base=220&pct=0.9", http.StatusBadRequest, 0, 0},
+		{"?average=180", http.StatusBadRequest, 0, 0},
+	}
+	game := NewGame()
+	game.rollMixedGame()
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ScoreHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/score"+tt.query, nil))
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status of %d, but it was %d instead.", tt.status, rec.Code)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var got struct {
+				Score    int `json:"score"`
+				Handicap int `json:"handicap"`
+			}
+			json.NewDecoder(rec.Body).Decode(&got)
+			if got.Score != tt.score || got.Handicap != tt.handicap {
+				t.Errorf("Expected score %d with handicap %d, but it was %d with %d instead.", tt.score, tt.handicap, got.Score, got.Handicap)
+			}
+		})
+	}
+}
+
+func TestTenthFrameValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		tenth []int
+		score int
+		err   error
+	}{
+		{"strike then a spare fill", []int{10, 7, 3}, 20, nil},
+		{"spare then a fill ball", []int{9, 1, 5}, 15, nil},
+		{"strike then overfilled fill balls", []int{10, 7, 5}, 17, ErrFrameOverfill},
+		{"open frame overfill", []int{6, 5}, 6, ErrFrameOverfill},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := NewGame()
+			game.rollMany(18, 0)
+			var err error
+			for _, pins := range tt.tenth {
+				if err = game.Roll(pins); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected error %v, but it was %v instead.", tt.err, err)
+			}
+			if score := game.Score(); score != tt.score {
+				t.Errorf("Expected score of %d, but it was %d instead.", tt.score, score)
+			}
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Log("Rolling a strike on a clone of a 3, 4 game... (expected original score: 7)")
+	game := NewGame()
+	game.Roll(3)
+	game.Roll(4)
+
+	clone := game.Clone()
+	clone.rollStrike()
+	clone.Roll(2)
+
+	if score := game.Score(); score != 7 {
+		t.Errorf("Expected original score of 7, but it was %d instead.", score)
+	}
+	if rolls := game.Rolls(); len(rolls) != 2 {
+		t.Errorf("Expected original to have 2 rolls, but it had %d instead.", len(rolls))
+	}
+	if score := clone.Score(); score != 21 {
+		t.Errorf("Expected clone score of 21, but it was %d instead.", score)
+	}
+}
+
+// decodeErrorCode decodes the JSON error envelope written to rec and returns
+// its error code.
+func decodeErrorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected a JSON error body, but Content-Type was %q instead.", ct)
+	}
+	var envelope struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&envelope); err != nil {
+		t.Fatalf("Expected a JSON error envelope, but got %v.", err)
+	}
+	if envelope.Error.Message == "" {
+		t.Error("Expected an error message, but it was empty.")
+	}
+	return envelope.Error.Code
+}
+
+func TestRollHandlerMalformedBody(t *testing.T) {
+	t.Log("POSTing a malformed roll body... (expected status: 400, code: invalid_body)")
+	rec := httptest.NewRecorder()
+	RollHandler(NewGame())(rec, httptest.NewRequest(http.MethodPost, "/roll", strings.NewReader(`{"pins":`)))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status of 400, but it was %d instead.", rec.Code)
+	}
+	if code := decodeErrorCode(t, rec); code != "invalid_body" {
+		t.Errorf("Expected error code invalid_body, but it was %q instead.", code)
+	}
+}
+
+func TestWrongMethodEnvelope(t *testing.T) {
+	t.Log("GETting /roll... (expected status: 405, code: method_not_allowed)")
+	rec := httptest.NewRecorder()
+	RollHandler(NewGame())(rec, httptest.NewRequest(http.MethodGet, "/roll", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status of 405, but it was %d instead.", rec.Code)
+	}
+	if code := decodeErrorCode(t, rec); code != "method_not_allowed" {
+		t.Errorf("Expected error code method_not_allowed, but it was %q instead.", code)
+	}
+}
+
+func TestRollHandlerGameOver(t *testing.T) {
+	t.Log("POSTing a roll after a perfect game... (expected status: 409, code: game_over)")
+	game := NewGame()
+	game.rollMany(12, 10)
+	rec := httptest.NewRecorder()
+	RollHandler(game)(rec, httptest.NewRequest(http.MethodPost, "/roll", strings.NewReader(`{"pins": 3}`)))
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected status of 409, but it was %d instead.", rec.Code)
+	}
+	if code := decodeErrorCode(t, rec); code != "game_over" {
+		t.Errorf("Expected error code game_over, but it was %q instead.", code)
+	}
+}
+
+func TestRollAll(t *testing.T) {
+	t.Log("Rolling X 7/ 9- X -8 8/ -6 X X X81 as one batch... (expected score: 167)")
+	game := NewGame()
+	if err := game.RollAll([]int{10, 7, 3, 9, 0, 10, 0, 8, 8, 2, 0, 6, 10, 10, 10, 8, 1}); err != nil {
+		t.Fatalf("Expected the batch to succeed, but it failed with %v.", err)
+	}
+
+	if score := game.Score(); score != 167 {
+		t.Errorf("Expected score of 167, but it was %d instead.", score)
+	}
+}
+
+func TestRollAllIsAtomic(t *testing.T) {
+	t.Log("Rolling a batch with an invalid 12 in the middle... (expected: whole batch rejected)")
+	game := NewGame()
+	game.Roll(4)
+
+	if err := game.RollAll([]int{5, 10, 12, 3}); !errors.Is(err, ErrInvalidPinCount) {
+		t.Errorf("Expected ErrInvalidPinCount, but it was %v instead.", err)
+	}
+	if rolls := game.Rolls(); !reflect.DeepEqual(rolls, []int{4}) {
+		t.Errorf("Expected rolls of [4], but they were %v instead.", rolls)
+	}
+	if score := game.Score(); score != 4 {
+		t.Errorf("Expected score of 4, but it was %d instead.", score)
+	}
+}
+
+func TestRollsHandler(t *testing.T) {
+	t.Log("POSTing a batch of a strike, 7 and 1... (expected score: 26, frames: 18, 26)")
+	game := NewGame()
+	rec := httptest.NewRecorder()
+	RollsHandler(game)(rec, httptest.NewRequest(http.MethodPost, "/rolls", strings.NewReader(`{"pins": [10, 7, 1]}`)))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status of 201, but it was %d instead.", rec.Code)
+	}
+	var got struct {
+		Score  int   `json:"score"`
+		Frames []int `json:"frames"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Score != 26 || !reflect.DeepEqual(got.Frames, []int{18, 26}) {
+		t.Errorf("Expected score 26 with frames [18 26], but it was %d with %v instead.", got.Score, got.Frames)
+	}
+}
+
+func TestRollsHandlerRejectsOverfill(t *testing.T) {
+	t.Log("POSTing a batch with an 8 then 5 frame... (expected status: 400, no rolls recorded)")
+	game := NewGame()
+	rec := httptest.NewRecorder()
+	RollsHandler(game)(rec, httptest.NewRequest(http.MethodPost, "/rolls", strings.NewReader(`{"pins": [10, 8, 5, 1]}`)))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status of 400, but it was %d instead.", rec.Code)
+	}
+	if code := decodeErrorCode(t, rec); code != "frame_overfill" {
+		t.Errorf("Expected error code frame_overfill, but it was %q instead.", code)
+	}
+	if rolls := game.Rolls(); len(rolls) != 0 {
+		t.Errorf("Expected no rolls, but they were %v instead.", rolls)
+	}
+}
+
+func TestRollsHandlerCancelled(t *testing.T) {
+	t.Log("POSTing a batch on a cancelled request... (expected: no rolls recorded)")
+	game := NewGame()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodPost, "/rolls", strings.NewReader(`{"pins": [10, 7, 1]}`)).WithContext(ctx)
+	RollsHandler(game)(httptest.NewRecorder(), req)
+
+	if rolls := game.Rolls(); len(rolls) != 0 {
+		t.Errorf("Expected no rolls, but they were %v instead.", rolls)
+	}
+}
+
+func TestScoreCacheInvalidation(t *testing.T) {
+	t.Log("Reading the score around rolls, an undo and a reset... (expected: always matches a fresh walk)")
+	game := NewGame()
+	check := func(step string) {
+		t.Helper()
+		if score, want := game.Score(), game.computeScore(); score != want {
+			t.Errorf("After %s expected score of %d, but it was %d instead.", step, want, score)
+		}
+	}
+	game.rollStrike()
+	check("a strike")
+	game.Roll(4)
+	check("a 4")
+	game.Undo()
+	check("an undo")
+	game.RollAll([]int{6, 4, 11})
+	check("a rejected batch")
+	game.Reset()
+	check("a reset")
+}
+
+func TestReleaseGame(t *testing.T) {
+	t.Log("Releasing a named, noted no-tap game and starting a new one... (expected: a fresh game)")
+	game := NewGame(WithNoTap(9))
+	game.SetName("Ann")
+	game.SetNote(1, "left the 7-pin")
+	game.rollMixedGame()
+	ReleaseGame(game)
+
+	for x := 0; x < 10; x++ {
+		game = NewGame()
+		if score := game.Score(); score != 0 {
+			t.Errorf("Expected score of 0, but it was %d instead.", score)
+		}
+		if rolls := game.Rolls(); len(rolls) != 0 {
+			t.Errorf("Expected no rolls, but there were %v.", rolls)
+		}
+		if name := game.Name(); name != "" {
+			t.Errorf("Expected no name, but it was %q.", name)
+		}
+		if notes := game.Notes(); notes[0] != "" {
+			t.Errorf("Expected no notes, but there were %q.", notes)
+		}
+		game.rollMany(20, 4)
+		if score := game.Score(); score != 80 {
+			t.Errorf("Expected a standard game scoring 80, but it was %d instead.", score)
+		}
+		ReleaseGame(game)
+	}
+}
+
+func BenchmarkNewGame(b *testing.B) {
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for x := 0; x < b.N; x++ {
+			ReleaseGame(NewGame())
+		}
+	})
+	b.Run("unpooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for x := 0; x < b.N; x++ {
+			NewGame()
+		}
+	})
+}
+
+func BenchmarkScore(b *testing.B) {
+	game := NewGame()
+	game.rollMixedGame()
+
+	b.Run("cached", func(b *testing.B) {
+		for x := 0; x < b.N; x++ {
+			game.Score()
+		}
+	})
+	b.Run("recomputed", func(b *testing.B) {
+		for x := 0; x < b.N; x++ {
+			game.mu.RLock()
+			game.computeScore()
+			game.mu.RUnlock()
+		}
+	})
+}
+
+func TestScoreHandlerNotModified(t *testing.T) {
+	t.Log("GETting /score again with the ETag just returned... (expected status: 304)")
+	game := NewGame()
+	game.Roll(7)
+	rec := httptest.NewRecorder()
+	ScoreHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/score", nil))
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected an ETag, but there was none.")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/score", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	ScoreHandler(game)(rec, req)
+
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("Expected status of 304, but it was %d instead.", rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("Expected an empty body, but it was %q.", rec.Body.String())
+	}
+}
+
+func TestScoreHandlerNegotiation(t *testing.T) {
+	t.Log("GETting /score accepting JSON and then HTML... (expected: a JSON score, then an HTML scorecard)")
+	game := NewGame()
+	game.rollMixedGame()
+
+	req := httptest.NewRequest(http.MethodGet, "/score", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	ScoreHandler(game)(rec, req)
+	var got struct {
+		Score int `json:"score"`
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected Content-Type of application/json, but it was %q instead.", ct)
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil || got.Score != 167 {
+		t.Errorf("Expected a JSON score of 167, but got %d with error %v.", got.Score, err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/score", nil)
+	req.Header.Set("Accept", "text/html")
+	rec = httptest.NewRecorder()
+	ScoreHandler(game)(rec, req)
+	if ct := rec.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Errorf("Expected Content-Type of text/html, but it was %q instead.", ct)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "<table") || !strings.Contains(body, "167") {
+		t.Errorf("Expected an HTML scorecard table of 167, but the body was:\n%s", body)
+	}
+}
+
+func TestScoreETagChanges(t *testing.T) {
+	t.Log("Comparing ETags around a roll and a reset... (expected: each one differs)")
+	game := NewGame()
+	game.Roll(0)
+	first, _ := game.ETag()
+
+	game.Roll(7)
+	afterRoll, _ := game.ETag()
+	if afterRoll == first {
+		t.Errorf("Expected the ETag to change after a roll, but it stayed %s.", first)
+	}
+
+	game.Reset()
+	afterReset, _ := game.ETag()
+	if afterReset == afterRoll || afterReset == first {
+		t.Errorf("Expected the ETag to change after a reset, but it was %s.", afterReset)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/score", nil)
+	req.Header.Set("If-None-Match", afterRoll)
+	rec := httptest.NewRecorder()
+	ScoreHandler(game)(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status of 200 for a stale ETag, but it was %d instead.", rec.Code)
+	}
+}
+
+func TestScoreETagAfterRename(t *testing.T) {
+	t.Log("GETting /score with an ETag from before a rename... (expected status: 200 with the new name)")
+	game := NewGame()
+	game.SetName("Ann")
+	game.Roll(7)
+	before, _ := game.ETag()
+
+	game.SetName("Bob")
+	req := httptest.NewRequest(http.MethodGet, "/score", nil)
+	req.Header.Set("If-None-Match", before)
+	rec := httptest.NewRecorder()
+	ScoreHandler(game)(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status of 200 after a rename, but it was %d instead.", rec.Code)
+	}
+	if got := rec.Header().Get("ETag"); got == before {
+		t.Errorf("Expected the ETag to change after a rename, but it stayed %s.", got)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `"name":"Bob"`) {
+		t.Errorf("Expected the new name in the body, but it was %s instead.", body)
+	}
+}
+
+func TestScoreETagAfterSameTotalEdit(t *testing.T) {
+	t.Log("Editing 3 4 to 4 3... (expected: the ETag changes though the score doesn't)")
+	game := NewGame()
+	game.RollAll([]int{3, 4})
+	before, _ := game.ETag()
+
+	if err := game.EditRoll(0, 4); err != nil {
+		t.Fatalf("Expected the first edit to succeed, but got %v.", err)
+	}
+	if err := game.EditRoll(1, 3); err != nil {
+		t.Fatalf("Expected the second edit to succeed, but got %v.", err)
+	}
+	if after, _ := game.ETag(); after == before {
+		t.Errorf("Expected the ETag to change after the edit, but it stayed %s.", before)
+	}
+}
+
+func TestScoreETagPerRepresentation(t *testing.T) {
+	t.Log("GETting /score as HTML with the JSON ETag... (expected: 200 with a different ETag)")
+	game := NewGame()
+	game.Roll(7)
+	rec := httptest.NewRecorder()
+	ScoreHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/score", nil))
+	jsonTag := rec.Header().Get("ETag")
+
+	req := httptest.NewRequest(http.MethodGet, "/score", nil)
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("If-None-Match", jsonTag)
+	rec = httptest.NewRecorder()
+	ScoreHandler(game)(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status of 200, but it was %d instead.", rec.Code)
+	}
+	if htmlTag := rec.Header().Get("ETag"); htmlTag == jsonTag || htmlTag == "" {
+		t.Errorf("Expected an HTML ETag other than %s, but it was %q.", jsonTag, htmlTag)
+	}
+}
+
+func TestOnCompleteAfterReset(t *testing.T) {
+	t.Log("Completing a game, resetting it, completing it, undoing into frame 9 and completing it again... (expected: the hook fires each time)")
+	var scores []int
+	game := NewGame(WithOnComplete(func(done CompletedGame) { scores = append(scores, done.Score) }))
+	game.rollMany(20, 4)
+	game.Reset()
+	game.rollMany(20, 3)
+	for x := 0; x < 3; x++ {
+		game.Undo()
+	}
+	game.rollMany(3, 0)
+
+	if !reflect.DeepEqual(scores, []int{80, 60, 51}) {
+		t.Errorf("Expected the hook to see scores [80 60 51], but it saw %v instead.", scores)
+	}
+}
+
+func TestEditRoll(t *testing.T) {
+	t.Log("Correcting X 7/ 9- to X 7/ 8- in the mixed game... (expected score: 165)")
+	game := NewGame()
+	game.rollMixedGame()
+
+	if err := game.EditRoll(3, 8); err != nil {
+		t.Fatalf("Expected the edit to succeed, but got %v.", err)
+	}
+	if score := game.Score(); score != 165 {
+		t.Errorf("Expected score of 165, but it was %d instead.", score)
+	}
+	if rolls := game.Rolls(); rolls[3] != 8 {
+		t.Errorf("Expected roll 3 to be 8, but it was %d instead.", rolls[3])
+	}
+}
+
+func TestEditRollRejected(t *testing.T) {
+	t.Log("Editing 2 3 8 1 into an overfilled frame, then a strike ahead of 3 8... (expected: both rejected, game untouched)")
+	game := NewGame()
+	for _, pins := range []int{2, 3, 8, 1} {
+		game.Roll(pins)
+	}
+
+	if err := game.EditRoll(1, 9); !errors.Is(err, ErrFrameOverfill) {
+		t.Errorf("Expected ErrFrameOverfill editing its own frame, but got %v instead.", err)
+	}
+	if err := game.EditRoll(0, 10); !errors.Is(err, ErrFrameOverfill) {
+		t.Errorf("Expected ErrFrameOverfill in a later frame, but got %v instead.", err)
+	}
+	if rolls := game.Rolls(); !reflect.DeepEqual(rolls, []int{2, 3, 8, 1}) {
+		t.Errorf("Expected rolls of [2 3 8 1], but they were %v instead.", rolls)
+	}
+	if err := game.EditRoll(4, 1); !errors.Is(err, ErrRollNotFound) {
+		t.Errorf("Expected ErrRollNotFound past the last roll, but got %v instead.", err)
+	}
+}
+
+func TestEditRollHandler(t *testing.T) {
+	t.Log("PATCHing /rolls/1 from 2 to 3... (expected status: 200, score: 10)")
+	game := NewGame()
+	game.Roll(7)
+	game.Roll(2)
+	rec := httptest.NewRecorder()
+	EditRollHandler(game)(rec, httptest.NewRequest(http.MethodPatch, "/rolls/1", strings.NewReader(`{"pins": 3}`)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status of 200, but it was %d instead.", rec.Code)
+	}
+	if score := game.Score(); score != 10 {
+		t.Errorf("Expected score of 10, but it was %d instead.", score)
+	}
+
+	rec = httptest.NewRecorder()
+	EditRollHandler(game)(rec, httptest.NewRequest(http.MethodPatch, "/rolls/9", strings.NewReader(`{"pins": 3}`)))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status of 404, but it was %d instead.", rec.Code)
+	}
+}
+
+func TestNextRollMax(t *testing.T) {
+	tests := []struct {
+		name  string
+		rolls []int
+		want  int
+	}{
+		{"first ball", nil, 10},
+		{"second ball after a 4", []int{4}, 6},
+		{"after a strike in the tenth", []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10}, 10},
+		{"after a strike and a 3 in the tenth", []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 3}, 7},
+		{"game over", []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Checking the next ball %s... (expected max: %d)", tt.name, tt.want)
+			game := NewGame()
+			game.RollAll(tt.rolls)
+			if got := game.NextRollMax(); got != tt.want {
+				t.Errorf("Expected a max of %d, but it was %d instead.", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRemainingBalls(t *testing.T) {
+	tests := []struct {
+		name  string
+		rolls []int
+		want  int
+	}{
+		{"fresh game", nil, 21},
+		{"after a strike", []int{10}, 19},
+		{"after an open first ball", []int{4}, 20},
+		{"open first ball in the tenth", []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3}, 2},
+		{"open tenth frame", []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3, 4}, 0},
+		{"perfect game", []int{10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Counting the balls left %s... (expected: %d)", tt.name, tt.want)
+			game := NewGame()
+			game.RollAll(tt.rolls)
+			if got := game.RemainingBalls(); got != tt.want {
+				t.Errorf("Expected %d balls remaining, but it was %d instead.", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNextHandler(t *testing.T) {
+	t.Log("GETting /next after a 4... (expected max_pins: 6)")
+	game := NewGame()
+	game.Roll(4)
+	rec := httptest.NewRecorder()
+	NextHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/next", nil))
+
+	var got struct {
+		MaxPins int `json:"max_pins"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.MaxPins != 6 {
+		t.Errorf("Expected max_pins of 6, but it was %d instead.", got.MaxPins)
+	}
+}
+
+func TestStandingPins(t *testing.T) {
+	tests := []struct {
+		name  string
+		rolls []int
+		want  int
+	}{
+		{"start of a frame", []int{3, 4}, 10},
+		{"after a 6", []int{6}, 4},
+		{"fresh tenth-frame ball after a strike", []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10}, 10},
+		{"after the last ball of X 8 1", []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 8, 1}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Counting standing pins %s... (expected: %d)", tt.name, tt.want)
+			game := NewGame()
+			game.RollAll(tt.rolls)
+			if got := game.StandingPins(); got != tt.want {
+				t.Errorf("Expected %d pins standing, but there were %d instead.", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPinsHandler(t *testing.T) {
+	t.Log("GETting /pins after a 6... (expected standing: 4)")
+	game := NewGame()
+	game.Roll(6)
+	rec := httptest.NewRecorder()
+	PinsHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/pins", nil))
+
+	var got struct {
+		Standing int `json:"standing"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Standing != 4 {
+		t.Errorf("Expected standing of 4, but it was %d instead.", got.Standing)
+	}
+}
+
+func TestValidateRolls(t *testing.T) {
+	tests := []struct {
+		name  string
+		rolls []int
+		index int
+		err   error
+	}{
+		{"valid sequence", []int{10, 7, 3, 9, 0, 10}, -1, nil},
+		{"negative value at index 3", []int{3, 4, 5, -1, 2}, 3, ErrInvalidPinCount},
+		{"frame overfill at index 5", []int{1, 2, 3, 4, 5, 6}, 5, ErrFrameOverfill},
+		{"past the end of the game", []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 20, ErrGameOver},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Validating %v... (expected index %d, error %v)", tt.rolls, tt.index, tt.err)
+			index, err := ValidateRolls(tt.rolls)
+			if index != tt.index {
+				t.Errorf("Expected index %d, but it was %d instead.", tt.index, index)
+			}
+			if tt.err == nil && err != nil || !errors.Is(err, tt.err) {
+				t.Errorf("Expected error %v, but it was %v instead.", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestNewGameFromRolls(t *testing.T) {
+	t.Log("Seeding X 7/ 9- and rolling on... (expected score: 48, then 58 after a strike)")
+	game, err := NewGameFromRolls([]int{10, 7, 3, 9, 0})
+	if err != nil {
+		t.Fatalf("Expected the seed to be accepted, but got %v.", err)
+	}
+	if score := game.Score(); score != 48 {
+		t.Errorf("Expected score of 48, but it was %d instead.", score)
+	}
+	if frame := game.CurrentFrame(); frame != 4 {
+		t.Errorf("Expected to resume in frame 4, but it was frame %d instead.", frame)
+	}
+	game.rollStrike()
+	if score := game.Score(); score != 58 {
+		t.Errorf("Expected score of 58, but it was %d instead.", score)
+	}
+}
+
+func TestNewGameFromRollsRejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		rolls []int
+		err   error
+	}{
+		{"over-long", []int{10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10}, ErrGameOver},
+		{"out of range", []int{3, 11}, ErrInvalidPinCount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Seeding a game with an %s sequence... (expected: rejected)", tt.name)
+			_, err := NewGameFromRolls(tt.rolls)
+			if !errors.Is(err, ErrInvalidGameState) || !errors.Is(err, tt.err) {
+				t.Errorf("Expected ErrInvalidGameState wrapping %v, but got %v instead.", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestScorePartialGameUnpadded(t *testing.T) {
+	tests := []struct {
+		name  string
+		rolls []int
+		want  int
+	}{
+		{"strike with no bonus balls", []int{10}, 10},
+		{"strike with one bonus ball", []int{10, 4}, 18},
+		{"spare with no bonus ball", []int{6, 4}, 10},
+		{"all strikes through the tenth's first ball", []int{10, 10, 10, 10, 10, 10, 10, 10, 10, 10}, 270},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Scoring a %s with rolls sized to fit... (expected score: %d)", tt.name, tt.want)
+			game := NewGame()
+			game.rolls, game.current = tt.rolls, len(tt.rolls)
+			if score := game.Score(); score != tt.want {
+				t.Errorf("Expected score of %d, but it was %d instead.", tt.want, score)
+			}
+		})
+	}
+}
+
+func TestPreviewHandler(t *testing.T) {
+	t.Log("Previewing a 7 after a strike... (expected preview score: 24, live score still 10)")
+	game := NewGame()
+	game.rollStrike()
+	rec := httptest.NewRecorder()
+	PreviewHandler(game)(rec, httptest.NewRequest(http.MethodPost, "/preview", strings.NewReader(`{"pins": 7}`)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status of 200, but it was %d instead.", rec.Code)
+	}
+	var got struct {
+		Score  int   `json:"score"`
+		Frames []int `json:"frames"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Score != 24 {
+		t.Errorf("Expected a preview score of 24, but it was %d instead.", got.Score)
+	}
+	if score, rolls := game.Score(), game.Rolls(); score != 10 || len(rolls) != 1 {
+		t.Errorf("Expected the live game to stay at 10 after one roll, but it was %d after %v.", score, rolls)
+	}
+}
+
+func TestPreviewHandlerInvalidPins(t *testing.T) {
+	t.Log("Previewing 5 after a 7... (expected status: 400, code: frame_overfill)")
+	game := NewGame()
+	game.Roll(7)
+	rec := httptest.NewRecorder()
+	PreviewHandler(game)(rec, httptest.NewRequest(http.MethodPost, "/preview", strings.NewReader(`{"pins": 5}`)))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status of 400, but it was %d instead.", rec.Code)
+	}
+	if code := decodeErrorCode(t, rec); code != "frame_overfill" {
+		t.Errorf("Expected error code frame_overfill, but it was %q instead.", code)
+	}
+}
+
+func TestPinsNeededFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		rolls    []int
+		target   int
+		needed   int
+		possible bool
+	}{
+		{"already met", []int{10, 10, 10}, 50, 0, true},
+		{"reachable", []int{10, 7, 3}, 200, 170, true},
+		{"impossible", []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 40, 40, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Asking what a %s target of %d needs... (expected: %d, possible %t)", tt.name, tt.target, tt.needed, tt.possible)
+			game := NewGame()
+			game.RollAll(tt.rolls)
+			needed, possible := game.PinsNeededFor(tt.target)
+			if needed != tt.needed || possible != tt.possible {
+				t.Errorf("Expected %d needed and possible %t, but it was %d and %t instead.", tt.needed, tt.possible, needed, possible)
+			}
+		})
+	}
+}
+
+func TestTargetHandler(t *testing.T) {
+	t.Log("GETting /target?score=200 on a fresh game, then without a score... (expected: 200 needed, then status 400)")
+	game := NewGame()
+	rec := httptest.NewRecorder()
+	TargetHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/target?score=200", nil))
+
+	var got struct {
+		Needed   int  `json:"needed"`
+		Possible bool `json:"possible"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Needed != 200 || !got.Possible {
+		t.Errorf("Expected 200 needed and possible, but it was %d and %t instead.", got.Needed, got.Possible)
+	}
+
+	rec = httptest.NewRecorder()
+	TargetHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/target", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status of 400, but it was %d instead.", rec.Code)
+	}
+}
+
+func TestProjectStrikeAndSpare(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []GameOption
+		strike int
+		spare  int
+	}{
+		{"a fresh game", nil, 300, 290},
+		{"a fresh 9-pin no-tap game", []GameOption{WithNoTap(9)}, 300, 290},
+		{"a fresh five-pin game", []GameOption{WithRules(FivePin)}, 450, 435},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Projecting %s... (expected: strike %d, spare %d)", tt.name, tt.strike, tt.spare)
+			game := NewGame(tt.opts...)
+			strike, spare, err := game.ProjectStrikeAndSpare()
+			if err != nil {
+				t.Fatalf("Expected projections, but got %v.", err)
+			}
+			if strike != tt.strike || spare != tt.spare {
+				t.Errorf("Expected a strike projection of %d and a spare projection of %d, but they were %d and %d instead.", tt.strike, tt.spare, strike, spare)
+			}
+			if strike <= spare {
+				t.Errorf("Expected the strike to project higher than the spare, but it was %d against %d.", strike, spare)
+			}
+		})
+	}
+}
+
+func TestProjectStrikeAndSpareSecondBall(t *testing.T) {
+	t.Log("Projecting after a 4... (expected: ErrNotFirstBall)")
+	game := NewGame()
+	game.Roll(4)
+
+	if _, _, err := game.ProjectStrikeAndSpare(); !errors.Is(err, ErrNotFirstBall) {
+		t.Errorf("Expected ErrNotFirstBall, but got %v instead.", err)
+	}
+}
+
+func TestRollHandlerBodyLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"an over-limit body", `{"pins": 3, "note": "` + strings.Repeat("x", maxBodyBytes) + `"}`},
+		{"trailing junk", `{"pins": 3} junk`},
+		{"a second object", `{"pins": 3}{"pins": 4}`},
+		{"a stray brace", `{"pins": 3}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("POSTing a roll with %s... (expected status: 400, no roll)", tt.name)
+			game := NewGame()
+			rec := httptest.NewRecorder()
+			RollHandler(game)(rec, httptest.NewRequest(http.MethodPost, "/roll", strings.NewReader(tt.body)))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status of 400, but it was %d instead.", rec.Code)
+			}
+			if rolls := game.Rolls(); len(rolls) != 0 {
+				t.Errorf("Expected no rolls, but they were %v instead.", rolls)
+			}
+		})
+	}
+}
+
+func TestTenthFrameScoring(t *testing.T) {
+	tests := []struct {
+		name  string
+		ninth []int
+		tenth []int
+		want  int
+	}{
+		{"X X X", []int{0, 0}, []int{10, 10, 10}, 30},
+		{"X X 5", []int{0, 0}, []int{10, 10, 5}, 25},
+		{"X 5 /", []int{0, 0}, []int{10, 5, 5}, 20},
+		{"X 5 3", []int{0, 0}, []int{10, 5, 3}, 18},
+		{"X - -", []int{0, 0}, []int{10, 0, 0}, 10},
+		{"5 / X", []int{0, 0}, []int{5, 5, 10}, 20},
+		{"5 / 5", []int{0, 0}, []int{5, 5, 5}, 15},
+		{"- / X", []int{0, 0}, []int{0, 10, 10}, 20},
+		{"5 3", []int{0, 0}, []int{5, 3}, 8},
+		{"X X X after a ninth-frame strike", []int{10}, []int{10, 10, 10}, 60},
+		{"X 5 3 after a ninth-frame strike", []int{10}, []int{10, 5, 3}, 43},
+		{"5 / X after a ninth-frame spare", []int{5, 5}, []int{5, 5, 10}, 35},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Rolling a tenth frame of %s... (expected score: %d)", tt.name, tt.want)
+			game := NewGame()
+			game.rollMany(16, 0)
+			if err := game.RollAll(append(append([]int(nil), tt.ninth...), tt.tenth...)); err != nil {
+				t.Fatalf("Expected the rolls to be accepted, but got %v.", err)
+			}
+
+			if !game.IsComplete() {
+				t.Error("Expected the game to be complete, but it wasn't.")
+			}
+			if score := game.Score(); score != tt.want {
+				t.Errorf("Expected score of %d, but it was %d instead.", tt.want, score)
+			}
+			if frames := game.FrameScores(); len(frames) != framesPerGame || frames[framesPerGame-1] != tt.want {
+				t.Errorf("Expected a final frame total of %d, but the frames were %v.", tt.want, frames)
+			}
+		})
+	}
+}
+
+func TestFinish(t *testing.T) {
+	t.Log("Finishing X 7/ 9- 4 partway through the game... (expected: complete, score 52, further rolls rejected)")
+	game := NewGame()
+	game.RollAll([]int{10, 7, 3, 9, 0, 4})
+
+	if score := game.Finish(); score != 52 {
+		t.Errorf("Expected a final score of 52, but it was %d instead.", score)
+	}
+	if !game.IsComplete() {
+		t.Error("Expected the game to be complete, but it wasn't.")
+	}
+	if err := game.Roll(5); !errors.Is(err, ErrGameOver) {
+		t.Errorf("Expected ErrGameOver, but got %v instead.", err)
+	}
+	if score := game.Finish(); score != 52 {
+		t.Errorf("Expected finishing again to keep the score at 52, but it was %d instead.", score)
+	}
+}
+
+func TestFinishHandler(t *testing.T) {
+	t.Log("POSTing /game/finish after a strike... (expected score: 10)")
+	game := NewGame()
+	game.rollStrike()
+	rec := httptest.NewRecorder()
+	FinishHandler(game)(rec, httptest.NewRequest(http.MethodPost, "/game/finish", nil))
+
+	var got struct {
+		Score int `json:"score"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Score != 10 || !game.IsComplete() {
+		t.Errorf("Expected a complete game scoring 10, but it scored %d (complete: %t).", got.Score, game.IsComplete())
+	}
+}
+
+func TestReplay(t *testing.T) {
+	t.Log("Replaying X 3 4 5... (expected totals: 0, 0, 24, 24)")
+	game := NewGame()
+	game.RollAll([]int{10, 3, 4, 5})
+
+	if totals, want := game.Replay(), []int{0, 0, 24, 24}; !reflect.DeepEqual(totals, want) {
+		t.Errorf("Expected totals of %v, but they were %v instead.", want, totals)
+	}
+}
+
+func TestScoreAtBall(t *testing.T) {
+	tests := []struct {
+		balls int
+		want  int
+	}{
+		{-3, 0},
+		{0, 0},
+		{1, 0},
+		{3, 20},
+		{4, 39},
+		{8, 74},
+		{17, 167},
+		{100, 167},
+	}
+	game := NewGame()
+	game.rollMixedGame()
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.balls), func(t *testing.T) {
+			t.Logf("Scoring X 7/ 9- X -8 8/ -6 X X X81 after %d balls... (expected: %d)", tt.balls, tt.want)
+			if score := game.ScoreAtBall(tt.balls); score != tt.want {
+				t.Errorf("Expected score of %d, but it was %d instead.", tt.want, score)
+			}
+		})
+	}
+}
+
+func TestReplayHandler(t *testing.T) {
+	t.Log("GETting /replay after 7 2... (expected totals: 0, 9)")
+	game := NewGame()
+	game.RollAll([]int{7, 2})
+	rec := httptest.NewRecorder()
+	ReplayHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/replay", nil))
+
+	var got []int
+	json.NewDecoder(rec.Body).Decode(&got)
+	if want := []int{0, 9}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected totals of %v, but they were %v instead.", want, got)
+	}
+}
+
+func TestAllowMethods(t *testing.T) {
+	t.Log("DELETEing /score registered for GET only... (expected: 405 with Allow: GET)")
+	game := NewGame()
+	rec := httptest.NewRecorder()
+	allowMethods(ScoreHandler(game), http.MethodGet)(rec, httptest.NewRequest(http.MethodDelete, "/score", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status of 405, but it was %d instead.", rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); allow != "GET" {
+		t.Errorf("Expected Allow: GET, but it was %q instead.", allow)
+	}
+	if code := decodeErrorCode(t, rec); code != "method_not_allowed" {
+		t.Errorf("Expected error code method_not_allowed, but it was %q instead.", code)
+	}
+}
+
+func TestByMethodAllow(t *testing.T) {
+	t.Log("PUTting /game, which takes GET and DELETE... (expected: 405 with Allow: DELETE, GET)")
+	game := NewGame()
+	handler := byMethod(map[string]http.HandlerFunc{
+		http.MethodGet:    GameStateHandler(game),
+		http.MethodDelete: ResetHandler(game),
+	})
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPut, "/game", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status of 405, but it was %d instead.", rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); allow != "DELETE, GET" {
+		t.Errorf("Expected Allow: DELETE, GET, but it was %q instead.", allow)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	t.Log("GETting an unknown path... (expected: 404 not_found)")
+	rec := httptest.NewRecorder()
+	notFound(rec, httptest.NewRequest(http.MethodGet, "/nope", nil))
+
+	if code := decodeErrorCode(t, rec); rec.Code != http.StatusNotFound || code != "not_found" {
+		t.Errorf("Expected a 404 not_found, but got %d %q.", rec.Code, code)
+	}
+}
+
+func TestRollHandlerMarks(t *testing.T) {
+	tests := []struct {
+		name   string
+		rolls  []int
+		body   string
+		status int
+		score  int
+		code   string
+	}{
+		{"strike mark", nil, `{"mark": "strike"}`, http.StatusCreated, 10, ""},
+		{"spare mark after a 6", []int{6}, `{"mark": "spare"}`, http.StatusCreated, 10, ""},
+		{"numeric pins", []int{6}, `{"pins": 3}`, http.StatusCreated, 9, ""},
+		{"spare mark on a first ball", nil, `{"mark": "spare"}`, http.StatusBadRequest, 0, "misplaced_mark"},
+		{"unknown mark", nil, `{"mark": "double"}`, http.StatusBadRequest, 0, "invalid_mark"},
+		{"pins and mark", nil, `{"pins": 10, "mark": "strike"}`, http.StatusBadRequest, 0, "invalid_body"},
+		{"an empty object", nil, `{}`, http.StatusBadRequest, 0, "invalid_body"},
+		{"a misspelled key", nil, `{"pinz": 7}`, http.StatusBadRequest, 0, "invalid_body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("POSTing %s after %v... (expected status %d)", tt.body, tt.rolls, tt.status)
+			game := NewGame()
+			game.RollAll(tt.rolls)
+			rec := httptest.NewRecorder()
+			RollHandler(game)(rec, httptest.NewRequest(http.MethodPost, "/roll", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected status of %d, but it was %d instead.", tt.status, rec.Code)
+			}
+			if tt.code != "" {
+				if code := decodeErrorCode(t, rec); code != tt.code {
+					t.Errorf("Expected error code %s, but it was %q instead.", tt.code, code)
+				}
+				return
+			}
+			if score := game.Score(); score != tt.score {
+				t.Errorf("Expected score of %d, but it was %d instead.", tt.score, score)
+			}
+		})
+	}
+}
+
+func TestRollHandlerMarkIdempotencyKey(t *testing.T) {
+	t.Log("POSTing a strike mark twice with the same Idempotency-Key... (expected: one strike)")
+	game := NewGame()
+	for x := 0; x < 2; x++ {
+		req := httptest.NewRequest(http.MethodPost, "/roll", strings.NewReader(`{"mark": "strike"}`))
+		req.Header.Set("Idempotency-Key", "abc")
+		RollHandler(game)(httptest.NewRecorder(), req)
+	}
+
+	if rolls := game.Rolls(); !reflect.DeepEqual(rolls, []int{10}) {
+		t.Errorf("Expected a single strike, but the rolls were %v.", rolls)
+	}
+}
+
+func TestRollHandlerContentTypes(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"form-encoded pins", "application/x-www-form-urlencoded", "pins=7", http.StatusCreated},
+		{"form-encoded mark", "application/x-www-form-urlencoded", "mark=strike", http.StatusCreated},
+		{"JSON pins", "application/json; charset=utf-8", `{"pins": 7}`, http.StatusCreated},
+		{"form-encoded garbage", "application/x-www-form-urlencoded", "pins=seven", http.StatusBadRequest},
+		{"JSON sent as a form", "application/x-www-form-urlencoded", `{"pins":7}`, http.StatusBadRequest},
+		{"an empty form", "application/x-www-form-urlencoded", "", http.StatusBadRequest},
+		{"plain text", "text/plain", "7", http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("POSTing %q as %s... (expected status %d)", tt.body, tt.contentType, tt.status)
+			req := httptest.NewRequest(http.MethodPost, "/roll", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			game := NewGame()
+			RollHandler(game)(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected status of %d, but it was %d instead.", tt.status, rec.Code)
+			}
+			if rolls := game.Rolls(); tt.status != http.StatusCreated && len(rolls) != 0 {
+				t.Errorf("Expected no rolls, but they were %v instead.", rolls)
+			}
+			if tt.status == http.StatusUnsupportedMediaType {
+				if code := decodeErrorCode(t, rec); code != "unsupported_media_type" {
+					t.Errorf("Expected error code unsupported_media_type, but it was %q instead.", code)
+				}
+			}
+		})
+	}
+}
+
+func TestRollHandlerFormResponse(t *testing.T) {
+	t.Log("POSTing pins=7 as a form and as JSON... (expected: the same response)")
+	responses := make([]string, 2)
+	for x, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/roll", strings.NewReader("pins=7")),
+		httptest.NewRequest(http.MethodPost, "/roll", strings.NewReader(`{"pins": 7}`)),
+	} {
+		if x == 0 {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		rec := httptest.NewRecorder()
+		RollHandler(NewGame())(rec, req)
+		responses[x] = rec.Body.String()
+	}
+
+	if responses[0] != responses[1] {
+		t.Errorf("Expected the same response, but got %q and %q.", responses[0], responses[1])
+	}
+}
+
+func TestPostRequiresJSON(t *testing.T) {
+	store := NewGameStore()
+	id, _, _ := store.Create()
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		path    string
+		body    string
+	}{
+		{"roll", RollHandler(NewGame()), "/roll", `{"pins": 7}`},
+		{"rolls", RollsHandler(NewGame()), "/rolls", `{"pins": [7, 2]}`},
+		{"preview", PreviewHandler(NewGame()), "/preview", `{"pins": 7}`},
+		{"games", GamesHandler(store), "/games", `{"name": "Ann"}`},
+		{"match", MatchHandler(NewMatch("Ann")), "/match", `{"players": ["Ann", "Bob"]}`},
+		{"series", CreateSeriesHandler(store), "/series", `{"games": ["` + id + `"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("POSTing a JSON body to /%s as text/plain... (expected status 415)", tt.name)
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "text/plain")
+			rec := httptest.NewRecorder()
+			tt.handler(rec, req)
+
+			if rec.Code != http.StatusUnsupportedMediaType {
+				t.Errorf("Expected status of 415, but it was %d instead.", rec.Code)
+			}
+			if code := decodeErrorCode(t, rec); code != "unsupported_media_type" {
+				t.Errorf("Expected error code unsupported_media_type, but it was %q instead.", code)
+			}
+		})
+	}
+}
+
+func TestBodyLimits(t *testing.T) {
+	store := NewGameStore()
+	id, _, _ := store.Create()
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		method  string
+		path    string
+		body    string
+	}{
+		{"rolls", RollsHandler(NewGame()), http.MethodPost, "/rolls", `{"pins": [7, 2]} junk`},
+		{"games", GamesHandler(store), http.MethodPost, "/games", `{"name": "Ann"}{"name": "Bob"}`},
+		{"match", MatchHandler(NewMatch("Ann")), http.MethodPost, "/match", `{"players": ["` + strings.Repeat("x", maxBodyBytes) + `"]}`},
+		{"rules", GameHandler(store), http.MethodPatch, "/games/" + id + "/rules", `{"ruleset": "five-pin"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Sending /%s an oversized or trailing body... (expected status 400)", tt.name)
+			rec := httptest.NewRecorder()
+			tt.handler(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status of 400, but it was %d instead.", rec.Code)
+			}
+		})
+	}
+}
+
+func TestRegisterRoutes(t *testing.T) {
+	rt := newRouter()
+	registerRoutes(rt, NewGame(), NewGameStore(), NewMatch(), new(atomic.Bool))
+	if rt.err != nil {
+		t.Fatalf("Expected the routes to register, but got %v.", rt.err)
+	}
+
+	tests := []struct {
+		path    string
+		pattern string
+	}{
+		{"/roll", "/roll"},
+		{"/score", "/score"},
+		{"/frames/3", "/frames/"},
+		{"/game", "/game"},
+		{"/games", "/games"},
+		{"/games/abc/score", "/games/"},
+		{"/compare/odds", "/compare/odds"},
+		{"/match/roll", "/match/roll"},
+		{"/healthz", "/healthz"},
+		{"/metrics", "/metrics"},
+		{"/nowhere", "/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Logf("Looking up the handler for %s... (expected pattern %s)", tt.path, tt.pattern)
+			if _, pattern := rt.mux.Handler(httptest.NewRequest(http.MethodGet, tt.path, nil)); pattern != tt.pattern {
+				t.Errorf("Expected the pattern %s, but it was %q instead.", tt.pattern, pattern)
+			}
+		})
+	}
+}
+
+func TestSubRouteAllow(t *testing.T) {
+	store := NewGameStore()
+	id, _, _ := store.Create()
+	rt := newRouter()
+	registerRoutes(rt, NewGame(), store, NewMatch(), new(atomic.Bool))
+	tests := []struct {
+		method string
+		path   string
+		allow  string
+	}{
+		{http.MethodDelete, "/games/" + id + "/score", "GET"},
+		{http.MethodGet, "/games/" + id + "/roll", "POST"},
+		{http.MethodPost, "/games/" + id + "/name", "GET, PUT"},
+		{http.MethodGet, "/series/x/games", "POST"},
+		{http.MethodPost, "/series/x", "GET"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			t.Logf("Sending %s %s... (expected: 405 with Allow: %s)", tt.method, tt.path, tt.allow)
+			rec := httptest.NewRecorder()
+			rt.mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != http.StatusMethodNotAllowed {
+				t.Errorf("Expected status of 405, but it was %d instead.", rec.Code)
+			}
+			if allow := rec.Header().Get("Allow"); allow != tt.allow {
+				t.Errorf("Expected Allow: %s, but it was %q instead.", tt.allow, allow)
+			}
+		})
+	}
+}
+
+func TestRouterDuplicate(t *testing.T) {
+	t.Log("Registering /score twice... (expected: ErrDuplicateRoute, first handler kept)")
+	first, second := NewGame(), NewGame()
+	first.Roll(7)
+	rt := newRouter()
+	rt.handle("/score", ScoreHandler(first), http.MethodGet)
+	rt.handle("/score", ScoreHandler(second), http.MethodGet)
+
+	if !errors.Is(rt.err, ErrDuplicateRoute) {
+		t.Errorf("Expected ErrDuplicateRoute, but it was %v instead.", rt.err)
+	}
+	rec := httptest.NewRecorder()
+	rt.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/score", nil))
+	if body := rec.Body.String(); !strings.Contains(body, `"score":7`) {
+		t.Errorf("Expected the first game's score of 7, but the body was %q.", body)
+	}
+}
+
+func TestRollAt(t *testing.T) {
+	tests := []struct {
+		name        string
+		rolls       []int
+		frame, ball int
+		err         error
+	}{
+		{"first ball of the game", nil, 1, 1, nil},
+		{"second ball of frame 2", []int{10, 3}, 2, 2, nil},
+		{"frame only", []int{10}, 2, 0, nil},
+		{"unchecked", []int{10}, 0, 0, nil},
+		{"stale frame", []int{10, 3, 4}, 2, 1, ErrPositionMismatch},
+		{"wrong ball", []int{3}, 1, 1, ErrPositionMismatch},
+		{"game over", []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 10, 3, ErrGameOver},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Rolling a 0 at frame %d ball %d after %v... (expected error: %v)", tt.frame, tt.ball, tt.rolls, tt.err)
+			game := NewGame()
+			game.RollAll(tt.rolls)
+
+			if err := game.RollAt(tt.frame, tt.ball, 0); !errors.Is(err, tt.err) {
+				t.Errorf("Expected error %v, but it was %v instead.", tt.err, err)
+			}
+			want := len(tt.rolls) + 1
+			if tt.err != nil {
+				want = len(tt.rolls)
+			}
+			if rolls := game.Rolls(); len(rolls) != want {
+				t.Errorf("Expected %d rolls, but there were %d.", want, len(rolls))
+			}
+		})
+	}
+}
+
+func TestRollHandlerStalePosition(t *testing.T) {
+	t.Log("POSTing a 5 asserted at frame 2 ball 1 after X 3 4... (expected: 409 naming ball 1 of frame 3, nothing rolled)")
+	game := NewGame()
+	game.RollAll([]int{10, 3, 4})
+	rec := httptest.NewRecorder()
+	body := strings.NewReader(`{"pins": 5, "frame": 2, "ball": 1}`)
+	RollHandler(game)(rec, httptest.NewRequest(http.MethodPost, "/roll", body))
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected status of 409, but it was %d instead.", rec.Code)
+	}
+	var got struct {
+		Error struct {
+			Code  string `json:"code"`
+			Frame int    `json:"frame"`
+			Ball  int    `json:"ball"`
+		} `json:"error"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Error.Code != "position_mismatch" || got.Error.Frame != 3 || got.Error.Ball != 1 {
+		t.Errorf("Expected a position_mismatch at frame 3 ball 1, but got %+v.", got.Error)
+	}
+	if rolls := game.Rolls(); !reflect.DeepEqual(rolls, []int{10, 3, 4}) {
+		t.Errorf("Expected the roll not to be applied, but the rolls were %v.", rolls)
+	}
+}
+
+func TestRollHandlerNegativePosition(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"a negative frame", `{"pins": 5, "frame": -1}`},
+		{"a negative ball", `{"pins": 5, "frame": 1, "ball": -2}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("POSTing a 5 asserted at %s... (expected status: 400, nothing rolled)", tt.name)
+			game := NewGame()
+			rec := httptest.NewRecorder()
+			RollHandler(game)(rec, httptest.NewRequest(http.MethodPost, "/roll", strings.NewReader(tt.body)))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status of 400, but it was %d instead.", rec.Code)
+			}
+			if code := decodeErrorCode(t, rec); code != "invalid_body" {
+				t.Errorf("Expected error code invalid_body, but it was %q instead.", code)
+			}
+			if rolls := game.Rolls(); len(rolls) != 0 {
+				t.Errorf("Expected no rolls, but they were %v instead.", rolls)
+			}
+		})
+	}
+}
+
+func TestRollHandlerCurrentPosition(t *testing.T) {
+	t.Log("POSTing a 5 asserted at frame 3 ball 1 after X 3 4... (expected: 201, score 29)")
+	game := NewGame()
+	game.RollAll([]int{10, 3, 4})
+	rec := httptest.NewRecorder()
+	body := strings.NewReader(`{"pins": 5, "frame": 3, "ball": 1}`)
+	RollHandler(game)(rec, httptest.NewRequest(http.MethodPost, "/roll", body))
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("Expected status of 201, but it was %d instead.", rec.Code)
+	}
+	if score := game.Score(); score != 29 {
+		t.Errorf("Expected score of 29, but it was %d instead.", score)
+	}
+}
@@ -0,0 +1,196 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: bowling.proto
+
+package bowlingpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	Bowling_NewGame_FullMethodName = "/bowling.v1.Bowling/NewGame"
+	Bowling_Roll_FullMethodName    = "/bowling.v1.Bowling/Roll"
+	Bowling_Score_FullMethodName   = "/bowling.v1.Bowling/Score"
+)
+
+// BowlingClient is the client API for Bowling service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Bowling scores games held by the server's game store.
+type BowlingClient interface {
+	// NewGame starts a new game and returns its ID.
+	NewGame(ctx context.Context, in *NewGameRequest, opts ...grpc.CallOption) (*NewGameResponse, error)
+	// Roll rolls the ball in a game and returns the updated score.
+	Roll(ctx context.Context, in *RollRequest, opts ...grpc.CallOption) (*RollResponse, error)
+	// Score returns the current score of a game.
+	Score(ctx context.Context, in *ScoreRequest, opts ...grpc.CallOption) (*ScoreResponse, error)
+}
+
+type bowlingClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBowlingClient(cc grpc.ClientConnInterface) BowlingClient {
+	return &bowlingClient{cc}
+}
+
+func (c *bowlingClient) NewGame(ctx context.Context, in *NewGameRequest, opts ...grpc.CallOption) (*NewGameResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NewGameResponse)
+	err := c.cc.Invoke(ctx, Bowling_NewGame_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bowlingClient) Roll(ctx context.Context, in *RollRequest, opts ...grpc.CallOption) (*RollResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RollResponse)
+	err := c.cc.Invoke(ctx, Bowling_Roll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bowlingClient) Score(ctx context.Context, in *ScoreRequest, opts ...grpc.CallOption) (*ScoreResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScoreResponse)
+	err := c.cc.Invoke(ctx, Bowling_Score_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BowlingServer is the server API for Bowling service.
+// All implementations must embed UnimplementedBowlingServer
+// for forward compatibility
+//
+// Bowling scores games held by the server's game store.
+type BowlingServer interface {
+	// NewGame starts a new game and returns its ID.
+	NewGame(context.Context, *NewGameRequest) (*NewGameResponse, error)
+	// Roll rolls the ball in a game and returns the updated score.
+	Roll(context.Context, *RollRequest) (*RollResponse, error)
+	// Score returns the current score of a game.
+	Score(context.Context, *ScoreRequest) (*ScoreResponse, error)
+	mustEmbedUnimplementedBowlingServer()
+}
+
+// UnimplementedBowlingServer must be embedded to have forward compatible implementations.
+type UnimplementedBowlingServer struct {
+}
+
+func (UnimplementedBowlingServer) NewGame(context.Context, *NewGameRequest) (*NewGameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewGame not implemented")
+}
+func (UnimplementedBowlingServer) Roll(context.Context, *RollRequest) (*RollResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Roll not implemented")
+}
+func (UnimplementedBowlingServer) Score(context.Context, *ScoreRequest) (*ScoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Score not implemented")
+}
+func (UnimplementedBowlingServer) mustEmbedUnimplementedBowlingServer() {}
+
+// UnsafeBowlingServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BowlingServer will
+// result in compilation errors.
+type UnsafeBowlingServer interface {
+	mustEmbedUnimplementedBowlingServer()
+}
+
+func RegisterBowlingServer(s grpc.ServiceRegistrar, srv BowlingServer) {
+	s.RegisterService(&Bowling_ServiceDesc, srv)
+}
+
+func _Bowling_NewGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BowlingServer).NewGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Bowling_NewGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BowlingServer).NewGame(ctx, req.(*NewGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bowling_Roll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BowlingServer).Roll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Bowling_Roll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BowlingServer).Roll(ctx, req.(*RollRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bowling_Score_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BowlingServer).Score(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Bowling_Score_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BowlingServer).Score(ctx, req.(*ScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Bowling_ServiceDesc is the grpc.ServiceDesc for Bowling service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Bowling_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bowling.v1.Bowling",
+	HandlerType: (*BowlingServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "NewGame",
+			Handler:    _Bowling_NewGame_Handler,
+		},
+		{
+			MethodName: "Roll",
+			Handler:    _Bowling_Roll_Handler,
+		},
+		{
+			MethodName: "Score",
+			Handler:    _Bowling_Score_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "bowling.proto",
+}
@@ -0,0 +1,5 @@
+// Package bowlingpb holds the generated protocol buffer and gRPC code for the
+// Bowling service defined in bowling.proto.
+package bowlingpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative bowling.proto
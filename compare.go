@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+)
+
+// oddsTrials is how many times OddsHandler plays out a pair of games.
+const oddsTrials = 1000
+
+// Comparison pits two games against each other frame by frame.
+type Comparison struct {
+	A playerCard `json:"a"`
+	B playerCard `json:"b"`
+
+	// Lead is how far A's running total is ahead of B's after each frame
+	// both games have scored, negative where B is ahead.
+	Lead []int `json:"lead"`
+
+	// DecidingFrame is the frame DecidingFrame reports.
+	DecidingFrame int `json:"deciding_frame"`
+
+	// Winner is "a" or "b" for whichever game has the higher score so far,
+	// or "tie".
+	Winner string `json:"winner"`
+}
+
+// Compare compares game a against game b.
+func Compare(a, b *Game) Comparison {
+	c := Comparison{A: cardOf(a), B: cardOf(b)}
+	c.Lead = leads(c.A.Frames, c.B.Frames)
+	c.DecidingFrame = decidingFrame(c.Lead)
+
+	switch {
+	case c.A.Score > c.B.Score:
+		c.Winner = "a"
+	case c.A.Score < c.B.Score:
+		c.Winner = "b"
+	default:
+		c.Winner = "tie"
+	}
+	return c
+}
+
+// DecidingFrame returns the last frame, numbered from 1, after which the lead
+// between games a and b changed hands, looking only at frames both games have
+// scored. Taking the lead from a tie counts as a change, and drawing level
+// does not, so a game led throughout was decided in frame 1. It returns 0 if
+// neither game has led yet.
+func DecidingFrame(a, b *Game) int {
+	return decidingFrame(leads(a.FrameScores(), b.FrameScores()))
+}
+
+// leads returns how far the running totals a are ahead of b after each frame
+// both have scored.
+func leads(a, b []int) []int {
+	lead := make([]int, min(len(a), len(b)))
+	for frame := range lead {
+		lead[frame] = a[frame] - b[frame]
+	}
+	return lead
+}
+
+// decidingFrame is DecidingFrame over the leads returned by leads.
+func decidingFrame(lead []int) int {
+	deciding, leader := 0, 0
+	for frame, diff := range lead {
+		sign := 0
+		switch {
+		case diff > 0:
+			sign = 1
+		case diff < 0:
+			sign = -1
+		}
+		if sign != 0 && sign != leader {
+			deciding, leader = frame+1, sign
+		}
+	}
+	return deciding
+}
+
+// Odds holds the estimated chances of each game in a comparison winning,
+// which together with Tie sum to 1.
+type Odds struct {
+	A   float64 `json:"a"`
+	B   float64 `json:"b"`
+	Tie float64 `json:"tie"`
+}
+
+// WinOdds estimates how likely game a is to beat game b by playing out the
+// remaining balls of copies of both, as in Simulate, trials times with rolls
+// drawn from rng. Once both games are complete the result is certain and
+// they are compared just once.
+func WinOdds(rng *rand.Rand, a, b *Game, trials int) Odds {
+	if a.RemainingBalls() == 0 && b.RemainingBalls() == 0 {
+		trials = 1
+	}
+	var wins, losses, ties int
+	for x := 0; x < trials; x++ {
+		finalA, finalB := a.Clone(), b.Clone()
+		playOut(rng, finalA)
+		playOut(rng, finalB)
+		switch diff := finalA.Score() - finalB.Score(); {
+		case diff > 0:
+			wins++
+		case diff < 0:
+			losses++
+		default:
+			ties++
+		}
+	}
+	n := float64(trials)
+	return Odds{A: float64(wins) / n, B: float64(losses) / n, Tie: float64(ties) / n}
+}
+
+// cardOf returns the scorecard of gm under its player's name.
+func cardOf(gm *Game) playerCard {
+	return playerCard{
+		Name:     gm.Name(),
+		Notation: gm.Notation(),
+		Frames:   gm.FrameScores(),
+		Score:    gm.Score(),
+	}
+}
+
+// endpoint handlers:
+
+// CompareHandler handles the "GET /compare?a={id}&b={id}" endpoint.
+func CompareHandler(store *GameStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		a, err := store.Get(r.URL.Query().Get("a"))
+		if err != nil {
+			writeGameError(w, err)
+			return
+		}
+		b, err := store.Get(r.URL.Query().Get("b"))
+		if err != nil {
+			writeGameError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(Compare(a, b))
+	}
+}
+
+// OddsHandler handles the "GET /compare/odds?a={id}&b={id}" endpoint, which
+// estimates each game's chance of winning with WinOdds. The optional "seed"
+// query parameter makes the estimate reproducible.
+func OddsHandler(store *GameStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		rng, err := seededRand(r)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid_query", "seed must be an integer")
+			return
+		}
+		a, err := store.Get(r.URL.Query().Get("a"))
+		if err != nil {
+			writeGameError(w, err)
+			return
+		}
+		b, err := store.Get(r.URL.Query().Get("b"))
+		if err != nil {
+			writeGameError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(WinOdds(rng, a, b, oddsTrials))
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	t.Log("Comparing a 300 game with a 20 game... (expected: a wins from frame 1, leading by 28 more each frame)")
+	high, low := NewGame(), NewGame()
+	high.rollMany(12, 10)
+	low.rollMany(20, 1)
+
+	c := Compare(high, low)
+	if c.Winner != "a" || c.DecidingFrame != 1 {
+		t.Errorf("Expected winner a, decided in frame 1, but got %q decided in frame %d.", c.Winner, c.DecidingFrame)
+	}
+	want := []int{28, 56, 84, 112, 140, 168, 196, 224, 252, 280}
+	if !reflect.DeepEqual(c.Lead, want) {
+		t.Errorf("Expected a lead of %v, but it was %v instead.", want, c.Lead)
+	}
+	if c.A.Score != 300 || c.B.Score != 20 {
+		t.Errorf("Expected totals of 300 and 20, but they were %d and %d instead.", c.A.Score, c.B.Score)
+	}
+}
+
+func TestDecidingFrame(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{"lead flips in frame 8", "9- 9- 9- 9- 9- 9- 9- -- -- --", "8- 8- 8- 8- 8- 8- 8- X -- --", 8},
+		{"led throughout", "X X X", "9- 9- 9-", 1},
+		{"drawn level and retaken", "9- -- 1-", "9- 1- --", 2},
+		{"never led", "3", "4", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Comparing %s with %s... (expected deciding frame: %d)", tt.a, tt.b, tt.want)
+			a, _ := ParseNotation(tt.a)
+			b, _ := ParseNotation(tt.b)
+
+			if frame := DecidingFrame(a, b); frame != tt.want {
+				t.Errorf("Expected deciding frame %d, but it was %d instead.", tt.want, frame)
+			}
+		})
+	}
+}
+
+func TestCompareTie(t *testing.T) {
+	t.Log("Comparing two new games... (expected: tie, no lead)")
+	if c := Compare(NewGame(), NewGame()); c.Winner != "tie" || len(c.Lead) != 0 {
+		t.Errorf("Expected a tie with no lead, but got winner %q and lead %v.", c.Winner, c.Lead)
+	}
+}
+
+func TestCompareHandler(t *testing.T) {
+	t.Log("GETting /compare for a low game against a high one, then an unknown ID... (expected: b wins, then 404)")
+	store := NewGameStore()
+	lowID, low, _ := store.Create()
+	highID, high, _ := store.Create()
+	low.rollMany(20, 1)
+	high.rollMixedGame()
+
+	rec := httptest.NewRecorder()
+	CompareHandler(store)(rec, httptest.NewRequest(http.MethodGet, "/compare?a="+lowID+"&b="+highID, nil))
+	var got Comparison
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Winner != "b" || got.B.Score != 167 {
+		t.Errorf("Expected b to win with 167, but got winner %q with %d.", got.Winner, got.B.Score)
+	}
+	if len(got.Lead) != 10 || got.Lead[9] != 20-167 {
+		t.Errorf("Expected a final lead of %d, but the lead was %v.", 20-167, got.Lead)
+	}
+
+	rec = httptest.NewRecorder()
+	CompareHandler(store)(rec, httptest.NewRequest(http.MethodGet, "/compare?a="+lowID+"&b=nope", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status of 404, but it was %d instead.", rec.Code)
+	}
+}
+
+func TestOddsHandler(t *testing.T) {
+	t.Log("GETting the odds of a game 7 strikes in against one of gutter balls... (expected: a at least 0.95)")
+	store := NewGameStore()
+	idA, a, _ := store.Create()
+	idB, b, _ := store.Create()
+	a.rollMany(7, 10)
+	b.rollMany(14, 0)
+
+	rec := httptest.NewRecorder()
+	OddsHandler(store)(rec, httptest.NewRequest(http.MethodGet, "/compare/odds?a="+idA+"&b="+idB+"&seed=42", nil))
+
+	var odds Odds
+	json.NewDecoder(rec.Body).Decode(&odds)
+	if odds.A < 0.95 {
+		t.Errorf("Expected a to be very likely to win, but the odds were %+v.", odds)
+	}
+	if sum := odds.A + odds.B + odds.Tie; sum < 0.999 || sum > 1.001 {
+		t.Errorf("Expected the odds to sum to 1, but they summed to %v.", sum)
+	}
+	if rolls := a.Rolls(); len(rolls) != 7 {
+		t.Errorf("Expected the stored game to be left alone, but it had %d rolls.", len(rolls))
+	}
+}
+
+func TestWinOddsComplete(t *testing.T) {
+	t.Log("Estimating the odds between two finished games of 80... (expected: a certain tie)")
+	a, b := NewGame(), NewGame()
+	a.rollMany(20, 4)
+	b.rollMany(20, 4)
+
+	if odds := WinOdds(rand.New(rand.NewSource(1)), a, b, oddsTrials); odds != (Odds{Tie: 1}) {
+		t.Errorf("Expected a certain tie, but the odds were %+v.", odds)
+	}
+}
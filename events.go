@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// ScoreEvent describes the state of a game after a roll is recorded.
+type ScoreEvent struct {
+	Score int `json:"score"`
+	Frame int `json:"frame"`
+}
+
+// Broker fans score events out to every listener subscribed to a game,
+// keyed by the game's ID. It is safe for concurrent use, and the zero value
+// is ready to use. Publishing on a nil Broker does nothing.
+type Broker struct {
+	mu   sync.Mutex
+	subs map[string]map[chan ScoreEvent]struct{}
+}
+
+// Subscribe registers a new listener for the game with gameID. The returned
+// function unregisters it and closes the channel; calling it again does
+// nothing.
+func (b *Broker) Subscribe(gameID string) (<-chan ScoreEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs == nil {
+		b.subs = make(map[string]map[chan ScoreEvent]struct{})
+	}
+	if b.subs[gameID] == nil {
+		b.subs[gameID] = make(map[chan ScoreEvent]struct{})
+	}
+	ch := make(chan ScoreEvent, 16)
+	b.subs[gameID][ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs[gameID], ch)
+			if len(b.subs[gameID]) == 0 {
+				delete(b.subs, gameID)
+			}
+			close(ch)
+		})
+	}
+}
+
+// Publish sends ev to every listener subscribed to the game with gameID,
+// dropping it for any listener that has fallen behind rather than blocking
+// the roll.
+func (b *Broker) Publish(gameID string, ev ScoreEvent) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[gameID] {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel that receives a ScoreEvent each time a roll is
+// recorded, and a function that cancels the subscription. Subscriptions are
+// made on the game's Broker, which is the GameStore's once it is stored, so
+// listeners subscribed before then are not told of later rolls.
+func (gm *Game) Subscribe() (<-chan ScoreEvent, func()) {
+	gm.mu.RLock()
+	events, id := gm.events, gm.id
+	gm.mu.RUnlock()
+	return events.Subscribe(id)
+}
+
+// endpoint handlers:
+
+// EventsHandler handles the "GET /events" endpoint, streaming a score event
+// to the client as Server-Sent Events after every roll.
+func EventsHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeJSONError(w, http.StatusInternalServerError, "streaming_unsupported", "Streaming unsupported")
+			return
+		}
+
+		events, unsubscribe := gm.Subscribe()
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case ev := <-events:
+				data, _ := json.Marshal(ev)
+				fmt.Fprintf(w, "data: %s\n\n", data)
+				flusher.Flush()
+			}
+		}
+	}
+}
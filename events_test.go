@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEventsStream(t *testing.T) {
+	t.Log("Streaming events while rolling a strike... (expected event: score 10, frame 1)")
+	game := NewGame()
+	server := httptest.NewServer(EventsHandler(game))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected to connect, but got %v.", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected Content-Type of text/event-stream, but it was %q instead.", ct)
+	}
+
+	game.rollStrike()
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("Expected to read an event, but got %v.", err)
+	}
+	var ev ScoreEvent
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(strings.TrimSpace(line), "data: ")), &ev); err != nil {
+		t.Fatalf("Expected a JSON event, but got %q.", line)
+	}
+	if ev.Score != 10 || ev.Frame != 1 {
+		t.Errorf("Expected score 10 in frame 1, but it was score %d in frame %d instead.", ev.Score, ev.Frame)
+	}
+}
+
+func TestUnsubscribeTwice(t *testing.T) {
+	t.Log("Cancelling a subscription twice... (expected: no panic, channel closed)")
+	game := NewGame()
+	events, unsubscribe := game.Subscribe()
+	unsubscribe()
+	unsubscribe()
+	game.Roll(4)
+
+	if _, ok := <-events; ok {
+		t.Error("Expected the channel to be closed, but it received an event.")
+	}
+}
+
+func TestEventsStopsOnCancel(t *testing.T) {
+	t.Log("Cancelling the request mid-stream... (expected: handler returns, subscription released)")
+	game := NewGame()
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/events", nil).WithContext(ctx)
+	done := make(chan struct{})
+	go func() {
+		EventsHandler(game)(httptest.NewRecorder(), req)
+		close(done)
+	}()
+
+	game.rollStrike()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the handler to return, but it was still streaming.")
+	}
+	game.events.mu.Lock()
+	defer game.events.mu.Unlock()
+	if n := len(game.events.subs); n != 0 {
+		t.Errorf("Expected no subscribers, but there were %d.", n)
+	}
+}
+
+func TestBrokerFansOut(t *testing.T) {
+	t.Log("Publishing to a game with two subscribers... (expected: both receive it, another game's subscriber doesn't)")
+	var b Broker
+	first, unsubscribeFirst := b.Subscribe("abc")
+	defer unsubscribeFirst()
+	second, unsubscribeSecond := b.Subscribe("abc")
+	defer unsubscribeSecond()
+	other, unsubscribeOther := b.Subscribe("xyz")
+	defer unsubscribeOther()
+
+	b.Publish("abc", ScoreEvent{Score: 10, Frame: 1})
+
+	for x, events := range []<-chan ScoreEvent{first, second} {
+		select {
+		case ev := <-events:
+			if ev != (ScoreEvent{Score: 10, Frame: 1}) {
+				t.Errorf("Expected score 10 in frame 1, but subscriber %d got %+v.", x+1, ev)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Expected subscriber %d to receive the event, but it didn't.", x+1)
+		}
+	}
+	select {
+	case ev := <-other:
+		t.Errorf("Expected no event for another game, but got %+v.", ev)
+	default:
+	}
+}
+
+func TestBrokerConcurrentUse(t *testing.T) {
+	t.Log("Subscribing, publishing and unsubscribing concurrently... (expected: no race, no subscribers left)")
+	var b Broker
+	var wg sync.WaitGroup
+	for x := 0; x < 10; x++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, unsubscribe := b.Subscribe("abc")
+			b.Publish("abc", ScoreEvent{Score: 1})
+			unsubscribe()
+			unsubscribe()
+		}()
+		go func() {
+			defer wg.Done()
+			b.Publish("abc", ScoreEvent{Score: 2})
+		}()
+	}
+	wg.Wait()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if n := len(b.subs); n != 0 {
+		t.Errorf("Expected no subscribed games, but there were %d.", n)
+	}
+}
+
+func TestStoreEvents(t *testing.T) {
+	t.Log("Subscribing to a stored game by ID and rolling a 7... (expected event: score 7)")
+	store := NewGameStore()
+	id, game, _ := store.Create()
+	events, unsubscribe := store.Events().Subscribe(id)
+	defer unsubscribe()
+
+	game.Roll(7)
+
+	select {
+	case ev := <-events:
+		if ev.Score != 7 {
+			t.Errorf("Expected score 7, but it was %d instead.", ev.Score)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected an event, but none arrived.")
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// WriteCSV writes every ball bowled so far to w as CSV, one row per ball
+// under a ball,frame,pins,mark,running_total header. Balls and frames are
+// numbered from 1, and mark is the ball's scorecard symbol. running_total is
+// only filled in on the last ball of each frame that has been scored, where
+// it is the frame's entry in FrameScores.
+func (gm *Game) WriteCSV(w io.Writer) error {
+	gm.mu.RLock()
+	frames, totals, rack := gm.frames(), gm.frameScores(), gm.rack
+	gm.mu.RUnlock()
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"ball", "frame", "pins", "mark", "running_total"})
+	ball := 0
+	for frame, balls := range frames {
+		for x, mark := range marks(balls, rack) {
+			ball++
+			total := ""
+			if x == len(balls)-1 && frame < len(totals) {
+				total = strconv.Itoa(totals[frame])
+			}
+			cw.Write([]string{strconv.Itoa(ball), strconv.Itoa(frame + 1), strconv.Itoa(balls[x]), mark, total})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// endpoint handlers:
+
+// ExportCSVHandler handles the "GET /games/{id}/export.csv" endpoint for the
+// stored game gm with the given ID.
+func ExportCSVHandler(id string, gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="game-%s.csv"`, id))
+		gm.WriteCSV(w)
+	}
+}
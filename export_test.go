@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	t.Log("Exporting X 7/ 9- X -8 8/ -6 X X X81... (expected: 17 ball rows whose totals match FrameScores)")
+	game := NewGame()
+	game.rollMixedGame()
+	var b strings.Builder
+	if err := game.WriteCSV(&b); err != nil {
+		t.Fatalf("Expected the CSV to be written, but got %v.", err)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid CSV, but got %v.", err)
+	}
+	if want := []string{"ball", "frame", "pins", "mark", "running_total"}; !reflect.DeepEqual(rows[0], want) {
+		t.Errorf("Expected a header of %v, but it was %v instead.", want, rows[0])
+	}
+	if len(rows) != 18 {
+		t.Fatalf("Expected 17 ball rows, but there were %d instead.", len(rows)-1)
+	}
+	if want := []string{"2", "2", "7", "7", ""}; !reflect.DeepEqual(rows[2], want) {
+		t.Errorf("Expected the second ball's row to be %v, but it was %v instead.", want, rows[2])
+	}
+
+	var totals []int
+	for _, row := range rows[1:] {
+		if row[4] != "" {
+			total, _ := strconv.Atoi(row[4])
+			totals = append(totals, total)
+		}
+	}
+	if want := game.FrameScores(); !reflect.DeepEqual(totals, want) {
+		t.Errorf("Expected running totals of %v, but they were %v instead.", want, totals)
+	}
+}
+
+func TestExportCSVHandler(t *testing.T) {
+	t.Log("GETting /games/{id}/export.csv... (expected: text/csv named after the game)")
+	store := NewGameStore()
+	id, game, _ := store.Create()
+	game.rollStrike()
+	rec := httptest.NewRecorder()
+	GameHandler(store)(rec, httptest.NewRequest(http.MethodGet, "/games/"+id+"/export.csv", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Errorf("Expected Content-Type of text/csv, but it was %q instead.", ct)
+	}
+	if cd, want := rec.Header().Get("Content-Disposition"), `attachment; filename="game-`+id+`.csv"`; cd != want {
+		t.Errorf("Expected Content-Disposition of %s, but it was %q instead.", want, cd)
+	}
+	if body, want := rec.Body.String(), "ball,frame,pins,mark,running_total\n1,1,10,X,\n"; body != want {
+		t.Errorf("Expected body %q, but it was %q instead.", want, body)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrFrameNotBowled is returned when a frame that has not been started is
+// asked for.
+var ErrFrameNotBowled = errors.New("frame not bowled")
+
+// FrameDetail describes a single frame as bowled so far.
+//
+// Kind is "strike", "spare" or "open", or empty while an open frame is still
+// in progress. Points is the frame's own score, including
+// whichever bonus balls have been bowled; BonusPending reports that a strike
+// or spare is still owed bonus or fill balls, so Points may yet grow. Note is
+// the frame's note from SetNote, if any.
+type FrameDetail struct {
+	Frame        int    `json:"frame"`
+	Balls        []int  `json:"balls"`
+	Kind         string `json:"kind,omitempty"`
+	Points       int    `json:"points"`
+	BonusPending bool   `json:"bonus_pending"`
+	Note         string `json:"note,omitempty"`
+}
+
+// FrameDetails returns the detail of every frame bowled so far, including a
+// frame in progress.
+func (gm *Game) FrameDetails() []FrameDetail {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	frames := gm.frames()
+	details := make([]FrameDetail, 0, len(frames))
+	throw := 0
+	for x, balls := range frames {
+		points, _, last := gm.frameAt(x, throw)
+		detail := FrameDetail{Frame: x + 1, Balls: balls, Points: points, Note: gm.notes[x]}
+		switch {
+		case gm.isStrike(throw):
+			detail.Kind = "strike"
+		case gm.isSpare(throw):
+			detail.Kind = "spare"
+		case len(balls) >= gm.rules.BallsPerFrame:
+			detail.Kind = "open"
+		}
+		detail.BonusPending = detail.Kind != "open" && detail.Kind != "" && last >= gm.current
+		details = append(details, detail)
+		throw += len(balls)
+	}
+	return details
+}
+
+// Frame returns the detail of frame n, numbered from 1, as FrameDetails
+// would. It returns ErrInvalidFrame if n is not between 1 and 10, and
+// ErrFrameNotBowled if no ball of the frame has been bowled yet.
+func (gm *Game) Frame(n int) (FrameDetail, error) {
+	if n < 1 || n > framesPerGame {
+		return FrameDetail{}, fmt.Errorf("%w: %d is not between 1 and %d", ErrInvalidFrame, n, framesPerGame)
+	}
+	details := gm.FrameDetails()
+	if n > len(details) {
+		return FrameDetail{}, fmt.Errorf("%w: frame %d", ErrFrameNotBowled, n)
+	}
+	return details[n-1], nil
+}
+
+// FrameMarks classifies each of the ten frames as "strike", "spare" or "open",
+// or "pending" while it is unbowled or in progress. The tenth frame joins the
+// mark of each rack it was bowled on with slashes, so a strike followed by a
+// strike and an open fill ball reads "strike/strike/open" and a spare with a
+// fill ball "spare/open" or "spare/strike".
+func (gm *Game) FrameMarks() []string {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	frames := gm.frames()
+	kinds := make([]string, framesPerGame)
+	for x := range kinds {
+		kinds[x] = "pending"
+		if x >= len(frames) {
+			continue
+		}
+		racks := gm.rackMarks(frames[x])
+		switch {
+		case x == framesPerGame-1:
+			if gm.isComplete() {
+				kinds[x] = strings.Join(racks, "/")
+			}
+		case racks[0] != "open" || len(frames[x]) == gm.rules.BallsPerFrame:
+			kinds[x] = racks[0]
+		}
+	}
+	return kinds
+}
+
+// rackMarks splits the balls of a frame into the racks they were bowled at
+// and classifies each as "strike", "spare" or "open". A rack cleared by a
+// third ball, as five-pin allows, is open, and so is a rack left standing
+// short of a full frame's balls, such as a fill ball or a frame in progress.
+// The caller must hold gm.mu.
+func (gm *Game) rackMarks(balls []int) []string {
+	var racks []string
+	standing, thrown := gm.rack, 0
+	for x, pins := range balls {
+		standing -= pins
+		thrown++
+		switch {
+		case standing == 0 && thrown == 1:
+			racks = append(racks, "strike")
+		case standing == 0 && thrown == 2:
+			racks = append(racks, "spare")
+		case standing == 0 || thrown == gm.rules.BallsPerFrame || x == len(balls)-1:
+			racks = append(racks, "open")
+		default:
+			continue
+		}
+		standing, thrown = gm.rack, 0
+	}
+	return racks
+}
+
+// endpoint handlers:
+
+// FrameDetailsHandler handles the "GET /frames/detail" endpoint.
+func FrameDetailsHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		response := struct {
+			Frames []FrameDetail `json:"frames"`
+		}{
+			Frames: gm.FrameDetails(),
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// FrameHandler handles the "GET /frames/{n}" endpoint, returning frame n's
+// detail along with its mark, as FrameMarks gives it, and the running total
+// through it, which is null until the frame is scored.
+func FrameHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/frames/"))
+		if err != nil {
+			writeJSONError(w, http.StatusNotFound, "not_found", "Not found")
+			return
+		}
+
+		detail, err := gm.Frame(n)
+		if err != nil {
+			writeGameError(w, err)
+			return
+		}
+		response := struct {
+			FrameDetail
+			Mark  string `json:"mark"`
+			Total *int   `json:"total"`
+		}{
+			FrameDetail: detail,
+			Mark:        gm.FrameMarks()[n-1],
+		}
+		if totals := gm.FrameScores(); n <= len(totals) {
+			response.Total = &totals[n-1]
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
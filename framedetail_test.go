@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFrameDetailsPendingStrike(t *testing.T) {
+	t.Log("Rolling a strike, then one bonus ball... (expected: strike of 13 points, bonus pending)")
+	game := NewGame()
+	game.rollStrike()
+	game.Roll(3)
+
+	want := []FrameDetail{
+		{Frame: 1, Balls: []int{10}, Kind: "strike", Points: 13, BonusPending: true},
+		{Frame: 2, Balls: []int{3}, Points: 3},
+	}
+	if details := game.FrameDetails(); !reflect.DeepEqual(details, want) {
+		t.Errorf("Expected details of %+v, but they were %+v instead.", want, details)
+	}
+}
+
+func TestFrameDetailsResolvedStrike(t *testing.T) {
+	t.Log("Rolling a strike, then 3 and 4... (expected: strike of 17 points, bonus resolved)")
+	game := NewGame()
+	game.rollStrike()
+	game.Roll(3)
+	game.Roll(4)
+
+	want := []FrameDetail{
+		{Frame: 1, Balls: []int{10}, Kind: "strike", Points: 17},
+		{Frame: 2, Balls: []int{3, 4}, Kind: "open", Points: 7},
+	}
+	if details := game.FrameDetails(); !reflect.DeepEqual(details, want) {
+		t.Errorf("Expected details of %+v, but they were %+v instead.", want, details)
+	}
+}
+
+func TestFrameDetailsTenthFrame(t *testing.T) {
+	t.Log("Rolling nine open frames, then a spare in the tenth... (expected: spare pending until the fill ball)")
+	game := NewGame()
+	game.rollMany(18, 0)
+	game.rollSpare()
+
+	tenth := game.FrameDetails()[framesPerGame-1]
+	if tenth.Kind != "spare" || tenth.Points != 10 || !tenth.BonusPending {
+		t.Errorf("Expected a pending spare of 10 points, but it was %+v instead.", tenth)
+	}
+
+	game.Roll(7)
+	tenth = game.FrameDetails()[framesPerGame-1]
+	if tenth.Points != 17 || tenth.BonusPending {
+		t.Errorf("Expected a resolved spare of 17 points, but it was %+v instead.", tenth)
+	}
+}
+
+func TestFrameDetailsHandler(t *testing.T) {
+	t.Log("GETting /frames/detail after a spare... (expected: one pending spare)")
+	game := NewGame()
+	game.rollSpare()
+	rec := httptest.NewRecorder()
+	FrameDetailsHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/frames/detail", nil))
+
+	var got struct {
+		Frames []FrameDetail `json:"frames"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if len(got.Frames) != 1 || got.Frames[0].Kind != "spare" || !got.Frames[0].BonusPending {
+		t.Errorf("Expected one pending spare, but the frames were %+v instead.", got.Frames)
+	}
+}
+
+func TestFrameMarks(t *testing.T) {
+	tests := []struct {
+		name     string
+		notation string
+		want     []string
+	}{
+		{"mixed game", "X 7/ 9- X -8 8/ -6 X X X81", []string{"strike", "spare", "open", "strike", "open", "spare", "open", "strike", "strike", "strike/open"}},
+		{"perfect game", "X X X X X X X X X XXX", []string{"strike", "strike", "strike", "strike", "strike", "strike", "strike", "strike", "strike", "strike/strike/strike"}},
+		{"spare with fill ball", "-- -- -- -- -- -- -- -- -- 9/8", []string{"open", "open", "open", "open", "open", "open", "open", "open", "open", "spare/open"}},
+		{"frame in progress", "X 3", []string{"strike", "pending", "pending", "pending", "pending", "pending", "pending", "pending", "pending", "pending"}},
+		{"tenth in progress", "X X X X X X X X X X", []string{"strike", "strike", "strike", "strike", "strike", "strike", "strike", "strike", "strike", "pending"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Marking %s... (expected %v)", tt.notation, tt.want)
+			game, err := ParseNotation(tt.notation)
+			if err != nil {
+				t.Fatalf("Expected notation to parse, but it failed with %v.", err)
+			}
+
+			if marks := game.FrameMarks(); !reflect.DeepEqual(marks, tt.want) {
+				t.Errorf("Expected marks of %v, but they were %v instead.", tt.want, marks)
+			}
+		})
+	}
+}
+
+func TestFivePinThirdBallClear(t *testing.T) {
+	t.Log("Marking a five-pin frame of 5, 5, 5... (expected: open in FrameMarks and FrameDetails, game not clean)")
+	game := NewGame(WithRules(FivePin))
+	game.RollAll([]int{5, 5, 5})
+	game.rollMany(9, 15)
+	game.rollMany(2, 15)
+
+	if mark := game.FrameMarks()[0]; mark != "open" {
+		t.Errorf("Expected FrameMarks to call frame 1 open, but it was %q instead.", mark)
+	}
+	if kind := game.FrameDetails()[0].Kind; kind != "open" {
+		t.Errorf("Expected FrameDetails to call frame 1 open, but it was %q instead.", kind)
+	}
+	if game.IsClean() {
+		t.Error("Expected the game not to be clean, but it was.")
+	}
+}
+
+func TestFrameHandler(t *testing.T) {
+	t.Log("GETting frame 2 of X 7/ 9-... (expected: spare of 19 points, mark spare, total 39)")
+	game, _ := ParseNotation("X 7/ 9-")
+	rec := httptest.NewRecorder()
+	FrameHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/frames/2", nil))
+
+	var got struct {
+		Frame  int    `json:"frame"`
+		Balls  []int  `json:"balls"`
+		Kind   string `json:"kind"`
+		Points int    `json:"points"`
+		Mark   string `json:"mark"`
+		Total  *int   `json:"total"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Frame != 2 || !reflect.DeepEqual(got.Balls, []int{7, 3}) || got.Kind != "spare" || got.Points != 19 || got.Mark != "spare" {
+		t.Errorf("Expected frame 2 to be a 7/ spare of 19 points, but it was %+v.", got)
+	}
+	if got.Total == nil || *got.Total != 39 {
+		t.Errorf("Expected a total of 39, but it was %v.", got.Total)
+	}
+}
+
+func TestFrameHandlerErrors(t *testing.T) {
+	tests := []struct {
+		path   string
+		status int
+		code   string
+	}{
+		{"/frames/4", http.StatusNotFound, "frame_not_bowled"},
+		{"/frames/11", http.StatusBadRequest, "invalid_frame"},
+		{"/frames/0", http.StatusBadRequest, "invalid_frame"},
+		{"/frames/x", http.StatusNotFound, "not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Logf("GETting %s after X 7/ 9-... (expected: %d %s)", tt.path, tt.status, tt.code)
+			game, _ := ParseNotation("X 7/ 9-")
+			rec := httptest.NewRecorder()
+			FrameHandler(game)(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if code := decodeErrorCode(t, rec); rec.Code != tt.status || code != tt.code {
+				t.Errorf("Expected %d %s, but got %d %s.", tt.status, tt.code, rec.Code, code)
+			}
+		})
+	}
+}
+
+func TestFrameHandlerPendingTotal(t *testing.T) {
+	t.Log("GETting frame 1 after a lone strike... (expected: total null)")
+	game := NewGame()
+	game.rollStrike()
+	rec := httptest.NewRecorder()
+	FrameHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/frames/1", nil))
+
+	if body := rec.Body.String(); !strings.Contains(body, `"total":null`) {
+		t.Errorf("Expected a null total, but the body was %s", body)
+	}
+}
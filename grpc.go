@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shaneavelino/bowling-api/bowlingpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// bowlingServer implements the Bowling gRPC service on top of a GameStore, so
+// games are shared with the HTTP endpoints under /games.
+type bowlingServer struct {
+	bowlingpb.UnimplementedBowlingServer
+	store *GameStore
+}
+
+// NewGRPCServer returns a gRPC server exposing the Bowling service for the
+// games in store.
+func NewGRPCServer(store *GameStore) *grpc.Server {
+	server := grpc.NewServer()
+	bowlingpb.RegisterBowlingServer(server, &bowlingServer{store: store})
+	return server
+}
+
+// NewGame implements bowlingpb.BowlingServer.
+func (s *bowlingServer) NewGame(ctx context.Context, req *bowlingpb.NewGameRequest) (*bowlingpb.NewGameResponse, error) {
+	if req.NoTap < 0 || req.NoTap >= allPins {
+		return nil, status.Error(codes.InvalidArgument, "no_tap must be between 0 and 9")
+	}
+	gm := NewGame(WithNoTap(int(req.NoTap)))
+	if req.Name != "" {
+		if err := gm.SetName(req.Name); err != nil {
+			return nil, grpcError(err)
+		}
+	}
+	id, err := s.store.Add(gm)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &bowlingpb.NewGameResponse{Id: id}, nil
+}
+
+// Roll implements bowlingpb.BowlingServer.
+func (s *bowlingServer) Roll(ctx context.Context, req *bowlingpb.RollRequest) (*bowlingpb.RollResponse, error) {
+	gm, err := s.store.Get(req.GameId)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if err := gm.Roll(int(req.Pins)); err != nil {
+		return nil, grpcError(err)
+	}
+	return &bowlingpb.RollResponse{
+		Score:  int32(gm.Score()),
+		Frames: int32s(gm.FrameScores()),
+	}, nil
+}
+
+// Score implements bowlingpb.BowlingServer.
+func (s *bowlingServer) Score(ctx context.Context, req *bowlingpb.ScoreRequest) (*bowlingpb.ScoreResponse, error) {
+	gm, err := s.store.Get(req.GameId)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &bowlingpb.ScoreResponse{
+		Score:    int32(gm.Score()),
+		Frames:   int32s(gm.FrameScores()),
+		Complete: gm.IsComplete(),
+	}, nil
+}
+
+// grpcError converts an error reported by a game or store into a gRPC status
+// error with a matching code.
+func grpcError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, ErrGameNotFound):
+		code = codes.NotFound
+	case errors.Is(err, ErrInvalidPinCount), errors.Is(err, ErrFrameOverfill), errors.Is(err, ErrInvalidName):
+		code = codes.InvalidArgument
+	case errors.Is(err, ErrGameOver):
+		code = codes.FailedPrecondition
+	case errors.Is(err, ErrTooManyGames):
+		code = codes.ResourceExhausted
+	}
+	return status.Error(code, err.Error())
+}
+
+// int32s converts a slice of ints for use in a protocol buffer message.
+func int32s(values []int) []int32 {
+	converted := make([]int32, len(values))
+	for x, v := range values {
+		converted[x] = int32(v)
+	}
+	return converted
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/shaneavelino/bowling-api/bowlingpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialBowling starts an in-process gRPC server for store and returns a
+// client connected to it.
+func dialBowling(t *testing.T, store *GameStore) bowlingpb.BowlingClient {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(store)
+	go server.Serve(ln)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ln.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Expected to dial the server, but got %v.", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return bowlingpb.NewBowlingClient(conn)
+}
+
+func TestGRPCRollAndScore(t *testing.T) {
+	t.Log("Creating a game over gRPC and rolling a strike, 3 and 4... (expected score: 24)")
+	store := NewGameStore()
+	client := dialBowling(t, store)
+	ctx := context.Background()
+
+	created, err := client.NewGame(ctx, &bowlingpb.NewGameRequest{Name: "ann"})
+	if err != nil {
+		t.Fatalf("Expected NewGame to succeed, but it failed with %v.", err)
+	}
+	for _, pins := range []int32{10, 3, 4} {
+		if _, err := client.Roll(ctx, &bowlingpb.RollRequest{GameId: created.Id, Pins: pins}); err != nil {
+			t.Fatalf("Expected Roll(%d) to succeed, but it failed with %v.", pins, err)
+		}
+	}
+
+	score, err := client.Score(ctx, &bowlingpb.ScoreRequest{GameId: created.Id})
+	if err != nil {
+		t.Fatalf("Expected Score to succeed, but it failed with %v.", err)
+	}
+	if score.Score != 24 || !reflect.DeepEqual(score.Frames, []int32{17, 24}) {
+		t.Errorf("Expected score 24 with frames [17 24], but it was %d with %v instead.", score.Score, score.Frames)
+	}
+	if gm, _ := store.Get(created.Id); gm.Score() != 24 || gm.Name() != "ann" {
+		t.Error("Expected the gRPC game to be shared with the store, but it was not.")
+	}
+}
+
+func TestGRPCErrors(t *testing.T) {
+	t.Log("Rolling 11 pins and scoring an unknown game over gRPC... (expected: InvalidArgument, NotFound)")
+	store := NewGameStore()
+	client := dialBowling(t, store)
+	ctx := context.Background()
+	id, _, _ := store.Create()
+
+	_, err := client.Roll(ctx, &bowlingpb.RollRequest{GameId: id, Pins: 11})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, but it was %v instead.", code)
+	}
+	_, err = client.Score(ctx, &bowlingpb.ScoreRequest{GameId: "nope"})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Errorf("Expected NotFound, but it was %v instead.", code)
+	}
+}
+
+func TestGRPCNewGameInvalidName(t *testing.T) {
+	t.Log("Creating a game over gRPC with a blank name... (expected: InvalidArgument, nothing stored)")
+	store := NewGameStore()
+	client := dialBowling(t, store)
+
+	_, err := client.NewGame(context.Background(), &bowlingpb.NewGameRequest{Name: "   "})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, but it was %v instead.", code)
+	}
+	if _, total := store.List(0, 10); total != 0 {
+		t.Errorf("Expected no games stored, but there were %d.", total)
+	}
+}
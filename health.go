@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+)
+
+// endpoint handlers:
+
+// HealthHandler handles the "GET /healthz" liveness endpoint. It never
+// touches game state, so probes can't contend with play.
+func HealthHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		writeStatus(w, http.StatusOK, "ok")
+	}
+}
+
+// ReadyHandler handles the "GET /readyz" readiness endpoint, reporting 503
+// until ready is set.
+func ReadyHandler(ready *atomic.Bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		if !ready.Load() {
+			writeStatus(w, http.StatusServiceUnavailable, "starting")
+			return
+		}
+		writeStatus(w, http.StatusOK, "ok")
+	}
+}
+
+// writeStatus writes a {"status": status} probe response.
+func writeStatus(w http.ResponseWriter, code int, status string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	response := struct {
+		Status string `json:"status"`
+	}{
+		Status: status,
+	}
+	json.NewEncoder(w).Encode(response)
+}
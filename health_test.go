@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHealthHandler(t *testing.T) {
+	t.Log("Probing /healthz without any game... (expected status: 200)")
+	rec := httptest.NewRecorder()
+	HealthHandler()(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status of 200, but it was %d instead.", rec.Code)
+	}
+	if body := strings.TrimSpace(rec.Body.String()); body != `{"status":"ok"}` {
+		t.Errorf("Expected body of {\"status\":\"ok\"}, but it was %s instead.", body)
+	}
+}
+
+func TestReadyHandler(t *testing.T) {
+	t.Log("Probing /readyz before and after start-up... (expected status: 503, then 200)")
+	ready := new(atomic.Bool)
+	handler := ReadyHandler(ready)
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status of 503, but it was %d instead.", rec.Code)
+	}
+
+	ready.Store(true)
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status of 200, but it was %d instead.", rec.Code)
+	}
+}
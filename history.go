@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HistoryEntry records a single roll: its one-based number within the game,
+// the pins it knocked down and when it was recorded. At is the zero time for
+// rolls restored from a saved game, which doesn't keep timestamps.
+type HistoryEntry struct {
+	Ball int       `json:"ball"`
+	Pins int       `json:"pins"`
+	At   time.Time `json:"at"`
+}
+
+// History returns every roll made so far, in order, with the time each was
+// recorded.
+func (gm *Game) History() []HistoryEntry {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	history := make([]HistoryEntry, gm.current)
+	for x := range history {
+		history[x] = HistoryEntry{Ball: x + 1, Pins: gm.rolls[x], At: gm.history[x]}
+	}
+	return history
+}
+
+// endpoint handlers:
+
+// HistoryHandler handles the "GET /history" endpoint.
+func HistoryHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		json.NewEncoder(w).Encode(gm.History())
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// stepClock returns a clock that reads start and then moves on by step
+// every time it is read.
+func stepClock(start time.Time, step time.Duration) func() time.Time {
+	next := start
+	return func() time.Time {
+		now := next
+		next = next.Add(step)
+		return now
+	}
+}
+
+func TestHistory(t *testing.T) {
+	t.Log("Rolling a strike and a 7... (expected: 2 entries with non-decreasing times)")
+	game := NewGame()
+	game.rollStrike()
+	game.Roll(7)
+
+	history := game.History()
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 history entries, but there were %d instead.", len(history))
+	}
+	if history[0].Ball != 1 || history[0].Pins != 10 || history[1].Ball != 2 || history[1].Pins != 7 {
+		t.Errorf("Expected balls 1 and 2 of 10 and 7 pins, but the history was %+v.", history)
+	}
+	if history[0].At.IsZero() || history[1].At.Before(history[0].At) {
+		t.Errorf("Expected non-decreasing timestamps, but they were %v then %v.", history[0].At, history[1].At)
+	}
+}
+
+func TestHistoryWithClock(t *testing.T) {
+	t.Log("Rolling three balls on a clock ticking a minute per roll... (expected: exact timestamps, kept by an edit)")
+	start := time.Date(2024, time.March, 1, 19, 0, 0, 0, time.UTC)
+	game := NewGame(WithClock(stepClock(start, time.Minute)))
+	game.RollAll([]int{3, 4, 10})
+	game.EditRoll(1, 5)
+
+	history := game.History()
+	for x, entry := range history {
+		if want := start.Add(time.Duration(x) * time.Minute); !entry.At.Equal(want) {
+			t.Errorf("Expected ball %d at %v, but it was at %v instead.", entry.Ball, want, entry.At)
+		}
+	}
+	if len(history) != 3 {
+		t.Errorf("Expected 3 history entries, but there were %d instead.", len(history))
+	}
+}
+
+func TestHistoryFollowsUndoAndReset(t *testing.T) {
+	t.Log("Rolling twice, undoing, then resetting... (expected history lengths: 1, then 0)")
+	game := NewGame()
+	game.Roll(3)
+	game.Roll(4)
+	game.Undo()
+	if n := len(game.History()); n != 1 {
+		t.Errorf("Expected 1 entry after an undo, but there were %d instead.", n)
+	}
+
+	game.Reset()
+	if n := len(game.History()); n != 0 {
+		t.Errorf("Expected no entries after a reset, but there were %d instead.", n)
+	}
+}
+
+func TestHistoryHandler(t *testing.T) {
+	t.Log("GETting /history after a 9... (expected: one entry of ball 1, 9 pins)")
+	game := NewGame()
+	game.Roll(9)
+	rec := httptest.NewRecorder()
+	HistoryHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/history", nil))
+
+	var got []HistoryEntry
+	json.NewDecoder(rec.Body).Decode(&got)
+	if len(got) != 1 || got[0].Ball != 1 || got[0].Pins != 9 {
+		t.Errorf("Expected one entry of ball 1 with 9 pins, but it was %+v instead.", got)
+	}
+}
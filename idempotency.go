@@ -0,0 +1,84 @@
+package main
+
+import (
+	"container/list"
+	"log/slog"
+)
+
+// idempotencyKeys is how many Idempotency-Key headers each game remembers.
+const idempotencyKeys = 64
+
+// keyCache remembers the score after each of the most recently recorded
+// keyed rolls, forgetting the least recently used key once it is full. The
+// zero value is ready to use and holds idempotencyKeys keys.
+type keyCache struct {
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// keyEntry is a remembered key and the score its roll left.
+type keyEntry struct {
+	key   string
+	score int
+}
+
+// get returns the score remembered for key, marking it as recently used.
+func (c *keyCache) get(key string) (int, bool) {
+	e, ok := c.entries[key]
+	if !ok {
+		return 0, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*keyEntry).score, true
+}
+
+// add remembers score for key, evicting the least recently used key if the
+// cache is full.
+func (c *keyCache) add(key string, score int) {
+	if c.entries == nil {
+		c.order = list.New()
+		c.entries = make(map[string]*list.Element)
+	}
+	if c.order.Len() >= idempotencyKeys {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*keyEntry).key)
+	}
+	c.entries[key] = c.order.PushFront(&keyEntry{key: key, score: score})
+}
+
+// RollOnce rolls pins as Roll does, unless a roll carrying key has already
+// been recorded, in which case it records nothing and returns the score as
+// it was after that roll. replayed reports which happened. Only successful
+// rolls are remembered, so a retry of a rejected roll is judged afresh. An
+// empty key is never remembered, so the roll is always made.
+func (gm *Game) RollOnce(key string, pins int) (score int, replayed bool, err error) {
+	return gm.rollOnce(key, func() (int, error) { return pins, nil })
+}
+
+// rollOnce is RollOnce with the pins worked out by next, which is only called
+// if the roll is made and then with gm.mu held, so that the pins may depend
+// on the balls before them. It takes gm.mu itself.
+func (gm *Game) rollOnce(key string, next func() (int, error)) (score int, replayed bool, err error) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	if score, ok := gm.keys.get(key); ok {
+		return score, true, nil
+	}
+
+	frame, _ := gm.cursor()
+	pins, err := next()
+	if err != nil {
+		return 0, false, err
+	}
+	if err := gm.roll(pins); err != nil {
+		return 0, false, err
+	}
+	slog.Debug("roll", "game", gm.id, "pins", pins)
+	gm.recorded(1, frame)
+	score = gm.score()
+	if key != "" {
+		gm.keys.add(key, score)
+	}
+	return score, false, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRollHandlerIdempotencyKey(t *testing.T) {
+	t.Log("POSTing a roll of 7 twice with the same Idempotency-Key... (expected: one roll, same response)")
+	game := NewGame()
+	var bodies [2]string
+	for x := range bodies {
+		req := httptest.NewRequest(http.MethodPost, "/roll", strings.NewReader(`{"pins": 7}`))
+		req.Header.Set("Idempotency-Key", "abc")
+		rec := httptest.NewRecorder()
+		RollHandler(game)(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Expected status of 201, but it was %d instead.", rec.Code)
+		}
+		bodies[x] = rec.Body.String()
+	}
+
+	if rolls := game.Rolls(); len(rolls) != 1 {
+		t.Errorf("Expected 1 roll, but there were %d instead.", len(rolls))
+	}
+	if bodies[0] != bodies[1] {
+		t.Errorf("Expected identical responses, but they were %q and %q.", bodies[0], bodies[1])
+	}
+}
+
+func TestKeyCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	t.Logf("Adding %d keys after looking up the first... (expected: the second evicted, the first kept)", idempotencyKeys+1)
+	var cache keyCache
+	for x := 0; x < idempotencyKeys; x++ {
+		cache.add(fmt.Sprint(x), x)
+	}
+	cache.get("0")
+	cache.add("new", 0)
+
+	if _, ok := cache.get("1"); ok {
+		t.Error("Expected key 1 to be evicted, but it was remembered.")
+	}
+	if score, ok := cache.get("0"); !ok || score != 0 {
+		t.Errorf("Expected key 0 to be remembered, but got %d, %t.", score, ok)
+	}
+}
+
+func TestRollOnceRetriesRejectedRoll(t *testing.T) {
+	t.Log("Retrying a key whose roll of 11 was rejected with a 5... (expected: the 5 is rolled)")
+	game := NewGame()
+	if _, _, err := game.RollOnce("k", 11); err == nil {
+		t.Error("Expected a roll of 11 to be rejected, but it was not.")
+	}
+
+	if score, replayed, err := game.RollOnce("k", 5); err != nil || replayed || score != 5 {
+		t.Errorf("Expected a fresh roll scoring 5, but got score %d, replayed %t, error %v.", score, replayed, err)
+	}
+}
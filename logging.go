@@ -0,0 +1,32 @@
+package main
+
+import (
+	"io"
+	"log"
+	"log/slog"
+	"os"
+)
+
+// parseLogLevel parses a -log-level value: debug, info, warn or error.
+func parseLogLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(s))
+	return level, err
+}
+
+// newLogger returns a logger writing text records at level and above to w.
+func newLogger(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// serverErrorLog routes the errors an http.Server reports, such as failed TLS
+// handshakes, through logger at error level.
+func serverErrorLog(logger *slog.Logger) *log.Logger {
+	return slog.NewLogLogger(logger.Handler(), slog.LevelError)
+}
+
+// fatal logs err at error level and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
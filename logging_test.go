@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want slog.Level
+	}{
+		{"debug", slog.LevelDebug},
+		{"info", slog.LevelInfo},
+		{"warn", slog.LevelWarn},
+		{"error", slog.LevelError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			t.Logf("Parsing -log-level %s... (expected level: %v)", tt.in, tt.want)
+			if got, err := parseLogLevel(tt.in); err != nil || got != tt.want {
+				t.Errorf("Expected level %v, but it was %v (error %v) instead.", tt.want, got, err)
+			}
+		})
+	}
+
+	if _, err := parseLogLevel("loud"); err == nil {
+		t.Error("Expected an error for an unknown level, but there was none.")
+	}
+}
+
+func TestLoggerHidesDebugAtInfo(t *testing.T) {
+	t.Log("Logging a roll at debug and a game at info, with an info logger... (expected: only the info record)")
+	var buf bytes.Buffer
+	logger := newLogger(&buf, slog.LevelInfo)
+
+	logger.Debug("roll", "game", "abc", "pins", 7)
+	logger.Info("game created", "id", "abc")
+
+	if out := buf.String(); strings.Contains(out, "msg=roll") || !strings.Contains(out, `msg="game created" id=abc`) {
+		t.Errorf("Expected only the game creation to be logged, but the log was %q.", out)
+	}
+}
+
+func TestServerErrorLog(t *testing.T) {
+	t.Log("Writing to the server's error log... (expected: an error-level record)")
+	var buf bytes.Buffer
+	serverErrorLog(newLogger(&buf, slog.LevelInfo)).Print("http: TLS handshake error")
+
+	if out := buf.String(); !strings.Contains(out, "level=ERROR") || !strings.Contains(out, "TLS handshake error") {
+		t.Errorf("Expected an error-level record, but the log was %q.", out)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// ErrNoPlayers is returned when a match is started without any players.
+var ErrNoPlayers = errors.New("match has no players")
+
+// Player is a named participant in a match.
+type Player struct {
+	Name string
+	Game *Game
+}
+
+// Match rotates a set of players through their games a frame at a time. It
+// is safe for concurrent use.
+type Match struct {
+	mu      sync.Mutex
+	players []Player
+	turn    int
+}
+
+// NewMatch starts a match between the named players, who bowl in the order
+// given. A match created without players must be started with Start before
+// anyone can roll.
+func NewMatch(names ...string) *Match {
+	m := new(Match)
+	m.Start(names...)
+	return m
+}
+
+// Start replaces the match with a fresh one between the named players. It
+// returns ErrNoPlayers, leaving the match empty, if names is empty.
+func (m *Match) Start(names ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.players = make([]Player, len(names))
+	for x, name := range names {
+		m.players[x] = Player{Name: name, Game: NewGame()}
+	}
+	m.turn = 0
+	if len(names) == 0 {
+		return ErrNoPlayers
+	}
+	return nil
+}
+
+// Players returns the players in bowling order.
+func (m *Match) Players() []Player {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	players := make([]Player, len(m.players))
+	copy(players, m.players)
+	return players
+}
+
+// CurrentPlayer returns the player who is up. It returns false if the match
+// has no players.
+func (m *Match) CurrentPlayer() (Player, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.players) == 0 {
+		return Player{}, false
+	}
+	return m.players[m.turn], true
+}
+
+// Roll rolls the ball for the current player. The turn passes to the next
+// player once the current player's frame is finished, so tenth-frame fill
+// balls stay with the player who earned them.
+func (m *Match) Roll(pins int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.players) == 0 {
+		return ErrNoPlayers
+	}
+
+	gm := m.players[m.turn].Game
+	frame := gm.CurrentFrame()
+	if err := gm.Roll(pins); err != nil {
+		return err
+	}
+	if gm.IsComplete() || gm.CurrentFrame() != frame {
+		m.turn = (m.turn + 1) % len(m.players)
+	}
+	return nil
+}
+
+// IsComplete reports whether every player has finished their game.
+func (m *Match) IsComplete() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, p := range m.players {
+		if !p.Game.IsComplete() {
+			return false
+		}
+	}
+	return len(m.players) > 0
+}
+
+// playerCard is one player's scorecard in a match response.
+type playerCard struct {
+	Name     string `json:"name"`
+	Notation string `json:"notation"`
+	Frames   []int  `json:"frames"`
+	Score    int    `json:"score"`
+}
+
+// endpoint handlers:
+
+// MatchHandler handles the "GET /match" endpoint, returning every player's
+// scorecard, and the "POST /match" endpoint, which starts a new match from
+// {"players": ["name", ...]}.
+func MatchHandler(m *Match) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			var body struct {
+				Players []string `json:"players"`
+			}
+			if !requireJSON(w, r) {
+				return
+			}
+			if err := decodeBody(w, r, &body); err != nil {
+				writeJSONError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+				return
+			}
+			if err := m.Start(body.Players...); err != nil {
+				writeJSONError(w, http.StatusBadRequest, "no_players", err.Error())
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		writeMatch(w, m)
+	}
+}
+
+// MatchRollHandler handles the "POST /match/roll" endpoint.
+func MatchRollHandler(m *Match) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		var roll struct {
+			Pins int `json:"pins"`
+		}
+		if !requireJSON(w, r) {
+			return
+		}
+		if err := decodeBody(w, r, &roll); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+			return
+		}
+
+		if err := m.Roll(roll.Pins); err != nil {
+			writeGameError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		writeMatch(w, m)
+	}
+}
+
+// writeMatch writes the scorecard of every player in m and who is up next.
+func writeMatch(w http.ResponseWriter, m *Match) {
+	players := m.Players()
+	cards := make([]playerCard, len(players))
+	for x, p := range players {
+		cards[x] = cardOf(p.Game)
+		cards[x].Name = p.Name
+	}
+
+	response := struct {
+		Players  []playerCard `json:"players"`
+		Current  string       `json:"current,omitempty"`
+		Complete bool         `json:"complete"`
+	}{
+		Players:  cards,
+		Complete: m.IsComplete(),
+	}
+	if current, ok := m.CurrentPlayer(); ok && !response.Complete {
+		response.Current = current.Name
+	}
+	json.NewEncoder(w).Encode(response)
+}
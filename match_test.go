@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMatchAlternatesFrames(t *testing.T) {
+	t.Log("Playing a two-player match, checking who is up before each ball")
+	match := NewMatch("ann", "bob")
+	steps := []struct {
+		player string
+		pins   int
+	}{
+		{"ann", 10},
+		{"bob", 3}, {"bob", 4},
+		{"ann", 5}, {"ann", 5},
+		{"bob", 10},
+	}
+	for x, step := range steps {
+		if p, _ := match.CurrentPlayer(); p.Name != step.player {
+			t.Fatalf("Before ball %d expected %s to be up, but it was %s instead.", x+1, step.player, p.Name)
+		}
+		if err := match.Roll(step.pins); err != nil {
+			t.Fatalf("Expected ball %d to succeed, but it failed with %v.", x+1, err)
+		}
+	}
+
+	players := match.Players()
+	if score := players[0].Game.Score(); score != 30 {
+		t.Errorf("Expected ann's score of 30, but it was %d instead.", score)
+	}
+	if score := players[1].Game.Score(); score != 17 {
+		t.Errorf("Expected bob's score of 17, but it was %d instead.", score)
+	}
+}
+
+func TestMatchTenthFrameFillBalls(t *testing.T) {
+	t.Log("Bowling a strike-filled tenth for the first player... (expected: fill balls stay with ann)")
+	match := NewMatch("ann", "bob")
+	for frame := 0; frame < 9; frame++ {
+		match.Roll(0)
+		match.Roll(0)
+		match.Roll(0)
+		match.Roll(0)
+	}
+	for x := 0; x < 3; x++ {
+		if p, _ := match.CurrentPlayer(); p.Name != "ann" {
+			t.Fatalf("Before tenth-frame ball %d expected ann to be up, but it was %s instead.", x+1, p.Name)
+		}
+		match.Roll(10)
+	}
+
+	if p, _ := match.CurrentPlayer(); p.Name != "bob" {
+		t.Errorf("Expected bob to be up after ann's tenth, but it was %s instead.", p.Name)
+	}
+	match.Roll(1)
+	match.Roll(2)
+	if !match.IsComplete() {
+		t.Error("Expected the match to be complete, but it was not.")
+	}
+	if err := match.Roll(1); !errors.Is(err, ErrGameOver) {
+		t.Errorf("Expected ErrGameOver, but it was %v instead.", err)
+	}
+}
+
+func TestMatchEndpoints(t *testing.T) {
+	t.Log("Starting a match, rolling a strike, and reading it back... (expected: bob up, ann on 10)")
+	match := NewMatch()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/match", MatchHandler(match))
+	mux.HandleFunc("/match/roll", MatchRollHandler(match))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/match", strings.NewReader(`{"players": ["ann", "bob"]}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status of 201, but it was %d instead.", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/match/roll", strings.NewReader(`{"pins": 10}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status of 201, but it was %d instead.", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/match", nil))
+	var got struct {
+		Players []playerCard `json:"players"`
+		Current string       `json:"current"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Current != "bob" {
+		t.Errorf("Expected bob to be up, but it was %q instead.", got.Current)
+	}
+	if len(got.Players) != 2 || got.Players[0].Score != 10 || got.Players[0].Notation != "X" {
+		t.Errorf("Expected ann to have a strike for 10, but the players were %+v.", got.Players)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// rollsTotal counts every roll recorded by any game.
+	rollsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bowling_rolls_total",
+		Help: "Total number of rolls recorded.",
+	})
+
+	// gameScore observes the final score of each completed game.
+	gameScore = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "bowling_game_score",
+		Help:    "Final scores of completed games.",
+		Buckets: prometheus.LinearBuckets(0, 30, 11),
+	})
+
+	// activeGames tracks the number of games held by game stores.
+	activeGames = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "bowling_active_games",
+		Help: "Number of games currently held by the server.",
+	})
+)
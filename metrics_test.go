@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// scrapeMetric fetches /metrics from handler and returns the value of the
+// named sample.
+func scrapeMetric(t *testing.T, handler http.Handler, name string) float64 {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == name {
+			value, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				t.Fatalf("Expected a numeric %s, but it was %q.", name, fields[1])
+			}
+			return value
+		}
+	}
+	t.Fatalf("Expected %s in the scrape, but it was missing.", name)
+	return 0
+}
+
+func TestMetricsCountRolls(t *testing.T) {
+	t.Log("Scraping /metrics around three rolls... (expected: bowling_rolls_total up by 3)")
+	handler := promhttp.Handler()
+	before := scrapeMetric(t, handler, "bowling_rolls_total")
+
+	game := NewGame()
+	game.Roll(3)
+	game.Roll(4)
+	game.Roll(11)
+	game.rollStrike()
+
+	if after := scrapeMetric(t, handler, "bowling_rolls_total"); after-before != 3 {
+		t.Errorf("Expected the roll counter to rise by 3, but it rose by %v instead.", after-before)
+	}
+}
+
+func TestMetricsObserveFinalScore(t *testing.T) {
+	t.Log("Scraping /metrics around a completed game... (expected: bowling_game_score_count up by 1)")
+	handler := promhttp.Handler()
+	before := scrapeMetric(t, handler, "bowling_game_score_count")
+
+	game := NewGame()
+	game.rollMany(25, 0)
+
+	if after := scrapeMetric(t, handler, "bowling_game_score_count"); after-before != 1 {
+		t.Errorf("Expected one final score observed, but there were %v instead.", after-before)
+	}
+}
+
+func TestMetricsActiveGames(t *testing.T) {
+	t.Log("Creating two stored games... (expected: bowling_active_games up by 2)")
+	handler := promhttp.Handler()
+	before := scrapeMetric(t, handler, "bowling_active_games")
+
+	store := NewGameStore()
+	store.Create()
+	store.Create()
+
+	if after := scrapeMetric(t, handler, "bowling_active_games"); after-before != 2 {
+		t.Errorf("Expected two more active games, but there were %v instead.", after-before)
+	}
+}
+
+func TestMetricsSkipPlayouts(t *testing.T) {
+	t.Log("Scraping /metrics around a simulated game and an odds estimate... (expected: no rolls, scores or hooks recorded)")
+	handler := promhttp.Handler()
+	rolls := scrapeMetric(t, handler, "bowling_rolls_total")
+	scores := scrapeMetric(t, handler, "bowling_game_score_count")
+
+	completed := 0
+	a := NewGame(WithOnComplete(func(CompletedGame) { completed++ }))
+	b := NewGame()
+	rng := rand.New(rand.NewSource(1))
+	Simulate(rng)
+	WinOdds(rng, a, b, 10)
+
+	if after := scrapeMetric(t, handler, "bowling_rolls_total"); after != rolls {
+		t.Errorf("Expected the roll counter to stay put, but it rose by %v instead.", after-rolls)
+	}
+	if after := scrapeMetric(t, handler, "bowling_game_score_count"); after != scores {
+		t.Errorf("Expected no final scores observed, but there were %v instead.", after-scores)
+	}
+	if completed != 0 {
+		t.Errorf("Expected the completion hook not to fire, but it fired %d times.", completed)
+	}
+}
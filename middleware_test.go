@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// captureLog sends the default logger's records to the returned buffer until
+// the test ends. SetDefault also redirects the log package, so its output is
+// put back too.
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous, output, flags := slog.Default(), log.Writer(), log.Flags()
+	slog.SetDefault(newLogger(&buf, slog.LevelInfo))
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+		log.SetOutput(output)
+		log.SetFlags(flags)
+	})
+	return &buf
+}
+
+func TestLogRequests(t *testing.T) {
+	t.Log("POSTing to /score through the logger... (expected log: POST /score 405)")
+	buf := captureLog(t)
+
+	rec := httptest.NewRecorder()
+	LogRequests(ScoreHandler(NewGame())).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/score", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status of 405, but it was %d instead.", rec.Code)
+	}
+	if line := buf.String(); !strings.Contains(line, "method=POST path=/score status=405 duration=") {
+		t.Errorf("Expected the request to be logged with status 405, but the log was %q.", line)
+	}
+}
+
+func TestLogRequestsImplicitStatus(t *testing.T) {
+	t.Log("GETting /score through the logger... (expected log: GET /score 200)")
+	buf := captureLog(t)
+
+	LogRequests(ScoreHandler(NewGame())).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/score", nil))
+
+	if line := buf.String(); !strings.Contains(line, "method=GET path=/score status=200 duration=") {
+		t.Errorf("Expected the request to be logged with status 200, but the log was %q.", line)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	t.Log("GETting a handler that panics twice... (expected: 500 both times, panic logged)")
+	buf := captureLog(t)
+
+	server := httptest.NewServer(Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("deliberate")
+	})))
+	defer server.Close()
+
+	for x := 0; x < 2; x++ {
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Expected the server to stay up, but request %d failed: %v.", x+1, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusInternalServerError {
+			t.Errorf("Expected status of 500, but it was %d instead.", resp.StatusCode)
+		}
+	}
+	if line := buf.String(); !strings.Contains(line, "level=ERROR msg=\"panic serving request\" method=GET path=/ panic=deliberate stack=") {
+		t.Errorf("Expected the panic to be logged, but the log was %q.", line)
+	}
+}
+
+func TestRecoverErrorBody(t *testing.T) {
+	t.Log("Recovering a panic through a recorder... (expected error code: internal)")
+	captureLog(t)
+
+	rec := httptest.NewRecorder()
+	Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("deliberate")
+	})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/score", nil))
+
+	if code := decodeErrorCode(t, rec); code != "internal" {
+		t.Errorf("Expected error code internal, but it was %q instead.", code)
+	}
+}
+
+func TestWithMiddlewareLogsPanics(t *testing.T) {
+	t.Log("GETting a panicking handler through the server middleware... (expected: 500 logged for the request)")
+	buf := captureLog(t)
+
+	rec := httptest.NewRecorder()
+	withMiddleware("*", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("deliberate")
+	})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/score", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status of 500, but it was %d instead.", rec.Code)
+	}
+	if line := buf.String(); !strings.Contains(line, "msg=request method=GET path=/score status=500 duration=") {
+		t.Errorf("Expected the request to be logged with status 500, but the log was %q.", line)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	t.Log("Sending an OPTIONS /roll preflight... (expected status: 204, CORS headers, game untouched)")
+	game := NewGame()
+	req := httptest.NewRequest(http.MethodOptions, "/roll", nil)
+	req.Header.Set("Origin", "https://scores.example")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	CORS("https://scores.example", RollHandler(game)).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status of 204, but it was %d instead.", rec.Code)
+	}
+	headers := map[string]string{
+		"Access-Control-Allow-Origin":  "https://scores.example",
+		"Access-Control-Allow-Methods": corsMethods,
+		"Access-Control-Allow-Headers": corsHeaders,
+	}
+	for name, want := range headers {
+		if got := rec.Header().Get(name); got != want {
+			t.Errorf("Expected %s of %q, but it was %q instead.", name, want, got)
+		}
+	}
+	if game.current != 0 {
+		t.Errorf("Expected no rolls, but there were %d.", game.current)
+	}
+}
+
+func TestCORSSimpleRequest(t *testing.T) {
+	t.Log("GETting /score through the default CORS policy... (expected Allow-Origin: *)")
+	rec := httptest.NewRecorder()
+	CORS("*", ScoreHandler(NewGame())).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/score", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status of 200, but it was %d instead.", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Expected Access-Control-Allow-Origin of *, but it was %q instead.", got)
+	}
+}
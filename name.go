@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidName is returned when a player name is blank or too long.
+var ErrInvalidName = errors.New("invalid name")
+
+// maxNameLength is the most characters a player name may have.
+const maxNameLength = 64
+
+// validateName checks that name can be given to a player.
+func validateName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: name is blank", ErrInvalidName)
+	}
+	if n := utf8.RuneCountInString(name); n > maxNameLength {
+		return fmt.Errorf("%w: %d characters is longer than %d", ErrInvalidName, n, maxNameLength)
+	}
+	return nil
+}
+
+// endpoint handlers:
+
+// NameHandler handles the "GET /games/{id}/name" endpoint and the
+// "PUT /games/{id}/name" endpoint, which names the player from
+// {"name": "..."}.
+func NameHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var body struct {
+				Name string `json:"name"`
+			}
+			if !requireJSON(w, r) {
+				return
+			}
+			if err := decodeBody(w, r, &body); err != nil {
+				writeJSONError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+				return
+			}
+			if err := gm.SetName(body.Name); err != nil {
+				writeGameError(w, err)
+				return
+			}
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			Name string `json:"name"`
+		}{gm.Name()})
+	}
+}
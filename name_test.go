@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNameEndpoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{"valid name", `{"name": "Ann"}`, http.StatusOK, "Ann"},
+		{"empty name", `{"name": ""}`, http.StatusBadRequest, "Bob"},
+		{"blank name", `{"name": "   "}`, http.StatusBadRequest, "Bob"},
+		{"too long a name", `{"name": "` + strings.Repeat("a", maxNameLength+1) + `"}`, http.StatusBadRequest, "Bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("PUTting %s to /games/{id}/name... (expected status %d, name %q)", tt.name, tt.status, tt.want)
+			store := NewGameStore()
+			id, game, _ := store.Create()
+			game.SetName("Bob")
+
+			rec := httptest.NewRecorder()
+			GameHandler(store)(rec, httptest.NewRequest(http.MethodPut, "/games/"+id+"/name", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected status of %d, but it was %d instead.", tt.status, rec.Code)
+			}
+			if tt.status == http.StatusBadRequest {
+				if code := decodeErrorCode(t, rec); code != "invalid_name" {
+					t.Errorf("Expected error code invalid_name, but it was %q instead.", code)
+				}
+			}
+			if name := game.Name(); name != tt.want {
+				t.Errorf("Expected the name to be %q, but it was %q instead.", tt.want, name)
+			}
+		})
+	}
+}
+
+func TestNameInScore(t *testing.T) {
+	t.Log("GETting the score of a game bowled by Ann... (expected name: Ann)")
+	game := NewGame()
+	game.SetName("Ann")
+	rec := httptest.NewRecorder()
+	ScoreHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/score", nil))
+
+	var response struct {
+		Name string `json:"name"`
+	}
+	json.NewDecoder(rec.Body).Decode(&response)
+	if response.Name != "Ann" {
+		t.Errorf("Expected the name Ann, but it was %q instead.", response.Name)
+	}
+	if card := game.ScoreCard(); !strings.HasPrefix(card, "Ann\n") {
+		t.Errorf("Expected the scorecard to be headed by Ann, but it was:\n%s", card)
+	}
+}
+
+func TestNameMarshaling(t *testing.T) {
+	t.Log("Round-tripping a named game through JSON... (expected name: Ann)")
+	game := NewGame()
+	game.SetName("Ann")
+	game.Roll(7)
+	data, _ := json.Marshal(game)
+
+	restored := NewGame()
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("Expected the game to unmarshal, but got %v instead.", err)
+	}
+	if name := restored.Name(); name != "Ann" {
+		t.Errorf("Expected the name Ann, but it was %q instead.", name)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidNotation is returned by ParseNotation for a malformed game.
+var ErrInvalidNotation = errors.New("invalid notation")
+
+// ErrInvalidMark is returned by RollMark for a mark it does not know.
+var ErrInvalidMark = errors.New("invalid mark")
+
+// ErrMisplacedMark is returned for a strike or spare marker on a ball that
+// can't be one, such as a spare on the first ball of a frame.
+var ErrMisplacedMark = errors.New("misplaced mark")
+
+// ParseNotation builds a game from standard bowling notation, where X is a
+// strike, / a spare, - a gutter ball and a digit the number of pins knocked
+// down. Whitespace is ignored, so frames may be separated by spaces, e.g.
+// "X 7/ 9- X -8 8/ -6 X X X81". The game may be partial.
+func ParseNotation(s string) (*Game, error) {
+	gm := NewGame()
+	for pos, r := range s {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		if gm.isComplete() {
+			return nil, fmt.Errorf("%w: too many frames at position %d", ErrInvalidNotation, pos)
+		}
+
+		pins, err := gm.markPins(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w at position %d: %w", ErrInvalidNotation, pos, err)
+		}
+
+		if err := gm.roll(pins); err != nil {
+			return nil, fmt.Errorf("%w at position %d: %v", ErrInvalidNotation, pos, err)
+		}
+	}
+	return gm, nil
+}
+
+// markRunes maps the names of the marks a scorer may roll by to their
+// notation.
+var markRunes = map[string]rune{"strike": 'X', "spare": '/'}
+
+// RollMark rolls the ball a scorer marked as "strike" or "spare", knocking
+// down a full rack or whatever the frame's earlier balls left standing, and
+// returns how many pins that was. It returns an error wrapping
+// ErrMisplacedMark for a spare on the first ball of a frame or a strike on a
+// later one, and ErrInvalidMark for any other mark.
+func (gm *Game) RollMark(mark string) (pins int, err error) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	frame, _ := gm.cursor()
+	if pins, err = gm.namedMarkPins(mark); err != nil {
+		return 0, err
+	}
+	if err := gm.roll(pins); err != nil {
+		return 0, err
+	}
+	slog.Debug("roll", "game", gm.id, "pins", pins, "mark", mark)
+	gm.recorded(1, frame)
+	return pins, nil
+}
+
+// namedMarkPins is markPins for a mark named as RollMark takes it; the caller
+// must hold gm.mu.
+func (gm *Game) namedMarkPins(mark string) (int, error) {
+	r, ok := markRunes[mark]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q is not strike or spare", ErrInvalidMark, mark)
+	}
+	return gm.markPins(r)
+}
+
+// markPins returns the number of pins the scorecard mark stands for on the
+// next ball: X, /, - or a digit. Strikes and spares are only accepted where
+// they can happen, and a digit that clears the pins must be marked instead.
+// The caller must hold gm.mu.
+func (gm *Game) markPins(mark rune) (int, error) {
+	fresh, standing := gm.freshRack(), gm.standingPins()
+	switch {
+	case mark == 'X' || mark == 'x':
+		if !fresh {
+			return 0, fmt.Errorf("%w: strike after the first ball of a frame", ErrMisplacedMark)
+		}
+		return gm.rack, nil
+	case mark == '/':
+		if fresh {
+			return 0, fmt.Errorf("%w: spare as the first ball of a frame", ErrMisplacedMark)
+		}
+		return standing, nil
+	case mark == '-':
+		return 0, nil
+	case mark >= '0' && mark <= '9':
+		pins := int(mark - '0')
+		if pins == standing {
+			return 0, fmt.Errorf("%w: %c clears the pins and must be marked X or /", ErrMisplacedMark, mark)
+		}
+		return pins, nil
+	}
+	return 0, fmt.Errorf("unexpected %q", mark)
+}
+
+// Notation renders the rolls made so far in standard bowling notation, with
+// frames separated by spaces and the tenth frame's fill balls kept with it,
+// e.g. "X 7/ 9- X -8 8/ -6 X X X81".
+func (gm *Game) Notation() string {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	frames := gm.frames()
+	notated := make([]string, len(frames))
+	for x, balls := range frames {
+		notated[x] = strings.Join(marks(balls, gm.rack), "")
+	}
+	return strings.Join(notated, " ")
+}
+
+// endpoint handlers:
+
+// NotationHandler handles the "GET /notation" endpoint.
+func NotationHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, gm.Notation())
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseNotationPerfectGame(t *testing.T) {
+	t.Log("Parsing X X X X X X X X X X X X... (expected score: 300)")
+	game, err := ParseNotation("X X X X X X X X X X X X")
+	if err != nil {
+		t.Fatalf("Expected notation to parse, but it failed with %v.", err)
+	}
+
+	if score := game.Score(); score != 300 {
+		t.Errorf("Expected score of 300, but it was %d instead.", score)
+	}
+}
+
+func TestParseNotationAllSpares(t *testing.T) {
+	t.Log("Parsing 9/ in every frame with a 9 fill ball... (expected score: 190)")
+	game, err := ParseNotation("9/ 9/ 9/ 9/ 9/ 9/ 9/ 9/ 9/ 9/9")
+	if err != nil {
+		t.Fatalf("Expected notation to parse, but it failed with %v.", err)
+	}
+
+	if score := game.Score(); score != 190 {
+		t.Errorf("Expected score of 190, but it was %d instead.", score)
+	}
+	if !game.IsComplete() {
+		t.Error("Expected game to be complete, but it was not.")
+	}
+}
+
+func TestParseNotationMixedGame(t *testing.T) {
+	t.Log("Parsing X 7/ 9- X -8 8/ -6 X X X81... (expected score: 167)")
+	game, err := ParseNotation("X 7/ 9- X -8 8/ -6 X X X81")
+	if err != nil {
+		t.Fatalf("Expected notation to parse, but it failed with %v.", err)
+	}
+
+	if score := game.Score(); score != 167 {
+		t.Errorf("Expected score of 167, but it was %d instead.", score)
+	}
+}
+
+func TestParseNotationMalformed(t *testing.T) {
+	tests := []struct {
+		name     string
+		notation string
+	}{
+		{"too many frames", "X X X X X X X X X X X X X"},
+		{"spare as first ball", "/5 X"},
+		{"garbage", "X 7/ 9? X"},
+		{"frame overfill", "X 78"},
+		{"strike as second ball", "5X"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseNotation(tt.notation); !errors.Is(err, ErrInvalidNotation) {
+				t.Errorf("Expected ErrInvalidNotation for %q, but it was %v instead.", tt.notation, err)
+			}
+		})
+	}
+}
+
+func TestParseNotationSpareFirstBall(t *testing.T) {
+	t.Log("Parsing /5 X... (expected: ErrMisplacedMark at position 0)")
+	_, err := ParseNotation("/5 X")
+	if !errors.Is(err, ErrInvalidNotation) || !errors.Is(err, ErrMisplacedMark) {
+		t.Fatalf("Expected ErrInvalidNotation and ErrMisplacedMark, but it was %v instead.", err)
+	}
+	if want := "invalid notation at position 0: misplaced mark: spare as the first ball of a frame"; err.Error() != want {
+		t.Errorf("Expected error %q, but it was %q instead.", want, err.Error())
+	}
+}
+
+func TestRollMark(t *testing.T) {
+	t.Log("Rolling a strike mark, a 6 and a spare mark... (expected: 10 then 4 pins, score 30)")
+	game := NewGame()
+	if pins, err := game.RollMark("strike"); err != nil || pins != 10 {
+		t.Errorf("Expected a strike of 10 pins, but got %d with error %v.", pins, err)
+	}
+	game.Roll(6)
+	if pins, err := game.RollMark("spare"); err != nil || pins != 4 {
+		t.Errorf("Expected a spare of 4 pins, but got %d with error %v.", pins, err)
+	}
+
+	if score := game.Score(); score != 30 {
+		t.Errorf("Expected score of 30, but it was %d instead.", score)
+	}
+}
+
+func TestRollMarkRejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		rolls []int
+		mark  string
+		err   error
+	}{
+		{"spare on a first ball", []int{3, 4}, "spare", ErrMisplacedMark},
+		{"strike on a second ball", []int{3}, "strike", ErrMisplacedMark},
+		{"unknown mark", nil, "turkey", ErrInvalidMark},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Rolling a %s mark after %v... (expected: %v, nothing rolled)", tt.mark, tt.rolls, tt.err)
+			game := NewGame()
+			game.RollAll(tt.rolls)
+
+			if _, err := game.RollMark(tt.mark); !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, but got %v instead.", tt.err, err)
+			}
+			if rolls := game.Rolls(); len(rolls) != len(tt.rolls) {
+				t.Errorf("Expected %d rolls, but there were %d.", len(tt.rolls), len(rolls))
+			}
+		})
+	}
+}
+
+func TestNotation(t *testing.T) {
+	tests := []struct {
+		name string
+		roll func(*Game)
+		want string
+	}{
+		{"mixed game", (*Game).rollMixedGame, "X 7/ 9- X -8 8/ -6 X X X81"},
+		{"perfect game", func(gm *Game) { gm.rollMany(12, 10) }, "X X X X X X X X X XXX"},
+		{"spare then strike in the tenth", func(gm *Game) { gm.rollMany(18, 0); gm.Roll(9); gm.Roll(1); gm.Roll(10) }, "-- -- -- -- -- -- -- -- -- 9/X"},
+		{"frame in progress", func(gm *Game) { gm.rollStrike(); gm.Roll(4) }, "X 4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := NewGame()
+			tt.roll(game)
+			if notation := game.Notation(); notation != tt.want {
+				t.Errorf("Expected notation of %q, but it was %q instead.", tt.want, notation)
+			}
+		})
+	}
+}
+
+func TestNotationRoundTrip(t *testing.T) {
+	t.Log("Parsing the notation of X 7/ 9- X -8 8/ -6 X X X81... (expected score: 167)")
+	game := NewGame()
+	game.rollMixedGame()
+
+	parsed, err := ParseNotation(game.Notation())
+	if err != nil {
+		t.Fatalf("Expected notation to parse, but it failed with %v.", err)
+	}
+	if score, want := parsed.Score(), game.Score(); score != want {
+		t.Errorf("Expected score of %d, but it was %d instead.", want, score)
+	}
+}
+
+func TestNotationFivePinThirdBallClear(t *testing.T) {
+	t.Log("Rendering five-pin frames of 5 5 5 and 13 2... (expected notation: 555 13/)")
+	game := NewGame(WithRules(FivePin))
+	game.RollAll([]int{5, 5, 5, 13, 2})
+
+	if notation := game.Notation(); notation != "555 13/" {
+		t.Errorf("Expected notation of %q, but it was %q instead.", "555 13/", notation)
+	}
+}
+
+func TestNotationHandler(t *testing.T) {
+	t.Log("GETting the notation of a spare... (expected body: 5/)")
+	game := NewGame()
+	game.rollSpare()
+	rec := httptest.NewRecorder()
+	NotationHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/notation", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("Expected Content-Type of text/plain, but it was %q instead.", ct)
+	}
+	if body := rec.Body.String(); body != "5/" {
+		t.Errorf("Expected body of %q, but it was %q instead.", "5/", body)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidFrame is returned when a frame number outside 1 to 10 is given.
+var ErrInvalidFrame = errors.New("invalid frame")
+
+// SetNote attaches a coach's note, such as "left the 7-pin", to frame, which
+// is numbered from 1, replacing any note it had. An empty note removes it.
+// Notes are kept until the game is reset, independently of the rolls, so a
+// frame may be noted before it is bowled. It returns ErrInvalidFrame if frame
+// is not between 1 and 10.
+func (gm *Game) SetNote(frame int, note string) error {
+	if frame < 1 || frame > framesPerGame {
+		return fmt.Errorf("%w: %d is not between 1 and %d", ErrInvalidFrame, frame, framesPerGame)
+	}
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	gm.notes[frame-1] = note
+	return nil
+}
+
+// Notes returns the note on each of the ten frames, or "" for a frame
+// without one.
+func (gm *Game) Notes() []string {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return append([]string(nil), gm.notes[:]...)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestSetNote(t *testing.T) {
+	t.Log("Noting frame 5 of the mixed game, then resetting... (expected: in the detail, then cleared)")
+	game := NewGame()
+	game.rollMixedGame()
+	if err := game.SetNote(5, "left the 7-pin"); err != nil {
+		t.Fatalf("Expected the note to be set, but got %v.", err)
+	}
+
+	if note := game.FrameDetails()[4].Note; note != "left the 7-pin" {
+		t.Errorf("Expected frame 5's note in the detail, but it was %q.", note)
+	}
+	if notes := game.Notes(); len(notes) != framesPerGame || notes[4] != "left the 7-pin" || notes[3] != "" {
+		t.Errorf("Expected only frame 5 noted, but the notes were %q.", notes)
+	}
+
+	game.Reset()
+	if notes := game.Notes(); notes[4] != "" {
+		t.Errorf("Expected the note to be cleared by the reset, but it was %q.", notes[4])
+	}
+}
+
+func TestSetNoteInvalidFrame(t *testing.T) {
+	t.Log("Noting frames 0 and 11... (expected: ErrInvalidFrame both times)")
+	game := NewGame()
+	for _, frame := range []int{0, 11} {
+		if err := game.SetNote(frame, "crossed over"); !errors.Is(err, ErrInvalidFrame) {
+			t.Errorf("Expected ErrInvalidFrame for frame %d, but got %v instead.", frame, err)
+		}
+	}
+}
+
+func TestNotesSurviveMarshaling(t *testing.T) {
+	t.Log("Round-tripping a game noted on frame 2 through JSON... (expected: the note kept)")
+	game := NewGame()
+	game.rollStrike()
+	game.SetNote(2, "crossed over")
+	data, _ := json.Marshal(game)
+
+	restored := new(Game)
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("Expected unmarshal to succeed, but it failed with %v.", err)
+	}
+	if notes := restored.Notes(); notes[1] != "crossed over" {
+		t.Errorf("Expected frame 2's note to be kept, but the notes were %q.", notes)
+	}
+}
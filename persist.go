@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ErrNoSavedGame is returned by LoadGame when there is no game saved at the
+// given path.
+var ErrNoSavedGame = errors.New("no saved game")
+
+// SaveGame writes gm to the file at path. The game is written to a temporary
+// file that is then renamed over path, so a crash mid-save leaves any
+// previously saved game intact.
+func SaveGame(path string, gm *Game) error {
+	data, err := json.Marshal(gm)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadGame reads a game previously written by SaveGame. It returns an error
+// wrapping ErrNoSavedGame if path does not exist.
+func LoadGame(path string) (*Game, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNoSavedGame, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	gm := new(Game)
+	if err := json.Unmarshal(data, gm); err != nil {
+		return nil, err
+	}
+	return gm, nil
+}
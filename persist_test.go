@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSaveAndLoadGame(t *testing.T) {
+	t.Log("Saving and loading X 7/ 9- X -8 8/ -6 X X X81... (expected score: 167)")
+	path := filepath.Join(t.TempDir(), "game.json")
+	game := NewGame()
+	game.rollMixedGame()
+
+	if err := SaveGame(path, game); err != nil {
+		t.Fatalf("Expected save to succeed, but it failed with %v.", err)
+	}
+	loaded, err := LoadGame(path)
+	if err != nil {
+		t.Fatalf("Expected load to succeed, but it failed with %v.", err)
+	}
+
+	if score := loaded.Score(); score != 167 {
+		t.Errorf("Expected score of 167, but it was %d instead.", score)
+	}
+	if rolls, want := loaded.Rolls(), game.Rolls(); !reflect.DeepEqual(rolls, want) {
+		t.Errorf("Expected rolls of %v, but they were %v instead.", want, rolls)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("Expected only the saved game on disk, but found %d files.", len(entries))
+	}
+}
+
+func TestLoadMissingGame(t *testing.T) {
+	t.Log("Loading a game that was never saved... (expected: ErrNoSavedGame)")
+	_, err := LoadGame(filepath.Join(t.TempDir(), "missing.json"))
+
+	if !errors.Is(err, ErrNoSavedGame) {
+		t.Errorf("Expected ErrNoSavedGame, but it was %v instead.", err)
+	}
+}
+
+func TestLoadedGameEvents(t *testing.T) {
+	t.Log("Subscribing to a loaded game and rolling a 4... (expected event: score 4)")
+	path := filepath.Join(t.TempDir(), "game.json")
+	SaveGame(path, NewGame())
+	loaded, err := LoadGame(path)
+	if err != nil {
+		t.Fatalf("Expected load to succeed, but it failed with %v.", err)
+	}
+	events, unsubscribe := loaded.Subscribe()
+	defer unsubscribe()
+
+	loaded.Roll(4)
+	if ev := <-events; ev.Score != 4 {
+		t.Errorf("Expected score 4, but it was %d instead.", ev.Score)
+	}
+}
+
+func TestValidateLoadedGame(t *testing.T) {
+	t.Log("Validating the mixed game after saving and loading it... (expected: valid)")
+	path := filepath.Join(t.TempDir(), "game.json")
+	game := NewGame()
+	game.rollMixedGame()
+	SaveGame(path, game)
+	loaded, err := LoadGame(path)
+	if err != nil {
+		t.Fatalf("Expected load to succeed, but it failed with %v.", err)
+	}
+
+	if err := loaded.Validate(); err != nil {
+		t.Errorf("Expected the game to be valid, but got %v.", err)
+	}
+}
+
+func TestValidateCorruptGame(t *testing.T) {
+	t.Log("Validating a game whose second frame was corrupted to 6 7... (expected: ErrInvalidGameState naming frame 2)")
+	game := NewGame()
+	game.RollAll([]int{10, 6, 3, 4})
+	game.rolls[2] = 7
+
+	err := game.Validate()
+	if !errors.Is(err, ErrInvalidGameState) || !errors.Is(err, ErrFrameOverfill) {
+		t.Errorf("Expected ErrInvalidGameState for an overfilled frame, but got %v instead.", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "roll 3 in frame 2") {
+		t.Errorf("Expected the error to name roll 3 in frame 2, but it was %v.", err)
+	}
+}
+
+func TestLoadCorruptGame(t *testing.T) {
+	t.Log("Loading a saved game whose first frame sums to 13... (expected: ErrInvalidGameState)")
+	path := filepath.Join(t.TempDir(), "game.json")
+	os.WriteFile(path, []byte(`{"rolls":[6,7],"current":2}`), 0o644)
+
+	if _, err := LoadGame(path); !errors.Is(err, ErrInvalidGameState) {
+		t.Errorf("Expected ErrInvalidGameState, but got %v instead.", err)
+	}
+}
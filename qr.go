@@ -0,0 +1,54 @@
+package main
+
+import (
+	"image/png"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// defaultQRSize, minQRSize and maxQRSize bound the width and height in
+// pixels of the QR codes served by QRHandler.
+const (
+	defaultQRSize = 256
+	minQRSize     = 64
+	maxQRSize     = 1024
+)
+
+// WriteQRCode writes the game's Token to w as a PNG image of a QR code,
+// size pixels square, so it can be scanned to resume the game elsewhere.
+func (gm *Game) WriteQRCode(w io.Writer, size int) error {
+	matrix, err := qrcode.NewQRCodeWriter().Encode(gm.Token(), gozxing.BarcodeFormat_QR_CODE, size, size, nil)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, matrix)
+}
+
+// endpoint handlers:
+
+// QRHandler handles the "GET /games/{id}/qr" endpoint, returning the game's
+// resume token as a QR code. The optional "size" query parameter sets its
+// width and height in pixels, from 64 to 1024.
+func QRHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+		size := defaultQRSize
+		if s := r.URL.Query().Get("size"); s != "" {
+			var err error
+			if size, err = strconv.Atoi(s); err != nil || size < minQRSize || size > maxQRSize {
+				writeJSONError(w, http.StatusBadRequest, "invalid_query", "size must be an integer between 64 and 1024")
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "image/png")
+		gm.WriteQRCode(w, size)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+func TestQRHandler(t *testing.T) {
+	t.Log("GETting /games/{id}/qr?size=300 for the mixed game and scanning it... (expected: a token for a 167 game)")
+	store := NewGameStore()
+	id, game, _ := store.Create()
+	game.rollMixedGame()
+
+	rec := httptest.NewRecorder()
+	GameHandler(store)(rec, httptest.NewRequest(http.MethodGet, "/games/"+id+"/qr?size=300", nil))
+
+	if contentType := rec.Header().Get("Content-Type"); contentType != "image/png" {
+		t.Errorf("Expected a Content-Type of image/png, but it was %q instead.", contentType)
+	}
+	img, err := png.Decode(rec.Body)
+	if err != nil {
+		t.Fatalf("Expected a PNG image, but got %v.", err)
+	}
+	if size := img.Bounds().Size(); size.X != 300 || size.Y != 300 {
+		t.Errorf("Expected a 300x300 image, but it was %v.", size)
+	}
+	bitmap, _ := gozxing.NewBinaryBitmapFromImage(img)
+	result, err := qrcode.NewQRCodeReader().Decode(bitmap, nil)
+	if err != nil {
+		t.Fatalf("Expected a readable QR code, but got %v.", err)
+	}
+
+	if tok := result.GetText(); tok != game.Token() {
+		t.Errorf("Expected the QR code to hold the game's token, but it held %q.", tok)
+	}
+	restored, err := GameFromToken(result.GetText())
+	if err != nil {
+		t.Fatalf("Expected the token to restore the game, but got %v.", err)
+	}
+	if score := restored.Score(); score != 167 {
+		t.Errorf("Expected score of 167, but it was %d instead.", score)
+	}
+}
+
+func TestQRHandlerInvalidSize(t *testing.T) {
+	tests := []string{"big", "10", "5000"}
+	for _, size := range tests {
+		t.Run(size, func(t *testing.T) {
+			t.Logf("GETting /qr?size=%s... (expected status 400)", size)
+			rec := httptest.NewRecorder()
+			QRHandler(NewGame())(rec, httptest.NewRequest(http.MethodGet, "/qr?size="+size, nil))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status of 400, but it was %d instead.", rec.Code)
+			}
+		})
+	}
+}
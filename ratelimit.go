@@ -0,0 +1,112 @@
+package main
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits how often each client IP may make requests, with a
+// token bucket per IP. It is safe for concurrent use.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+
+	// trustForwarded has the client IP taken from X-Forwarded-For, for
+	// servers behind a proxy that sets it.
+	trustForwarded bool
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// bucket holds the tokens a client had left when it last made a request.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter allows each client rate requests a second on average, in
+// bursts of up to burst requests. If trustForwarded is set, clients are told
+// apart by the first address in X-Forwarded-For rather than by RemoteAddr.
+func NewRateLimiter(rate float64, burst int, trustForwarded bool) *RateLimiter {
+	return &RateLimiter{
+		rate:           rate,
+		burst:          float64(burst),
+		trustForwarded: trustForwarded,
+		buckets:        make(map[string]*bucket),
+		now:            time.Now,
+	}
+}
+
+// Limit passes requests on to next while the client is within its limit, and
+// answers the rest with 429 Too Many Requests and a Retry-After header.
+func (l *RateLimiter) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wait, ok := l.allow(l.clientIP(r)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeJSONError(w, http.StatusTooManyRequests, "rate_limited", "Too many requests")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allow takes a token from ip's bucket if it has one. If not, it reports how
+// long until it will.
+func (l *RateLimiter) allow(ip string) (wait time.Duration, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.sweep(now)
+
+	b, found := l.buckets[ip]
+	if !found {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[ip] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / l.rate * float64(time.Second)), false
+	}
+	b.tokens--
+	return 0, true
+}
+
+// sweep forgets the buckets of clients idle long enough for their buckets to
+// have refilled, which are no different from new ones, at most once per
+// refill period. The caller must hold l.mu.
+func (l *RateLimiter) sweep(now time.Time) {
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	if now.Sub(l.lastSweep) < refill {
+		return
+	}
+	for ip, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, ip)
+		}
+	}
+	l.lastSweep = now
+}
+
+// clientIP returns the address r is rate limited by.
+func (l *RateLimiter) clientIP(r *http.Request) string {
+	if l.trustForwarded {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
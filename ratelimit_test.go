@@ -0,0 +1,75 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Log("POSTing rolls faster than 1 a second with a burst of 3... (expected: a 429 on the 4th, with Retry-After)")
+	limiter := NewRateLimiter(1, 3, false)
+	handler := limiter.Limit(RollHandler(NewGame()))
+
+	var rec *httptest.ResponseRecorder
+	for x := 0; x < 4; x++ {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/roll", strings.NewReader(`{"pins": 1}`)))
+		if x < 3 && rec.Code != http.StatusCreated {
+			t.Errorf("Expected request %d to get status 201, but it got %d instead.", x+1, rec.Code)
+		}
+	}
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status of 429, but it was %d instead.", rec.Code)
+	}
+	if retry := rec.Header().Get("Retry-After"); retry != "1" {
+		t.Errorf("Expected Retry-After of 1, but it was %q instead.", retry)
+	}
+	if code := decodeErrorCode(t, rec); code != "rate_limited" {
+		t.Errorf("Expected error code rate_limited, but it was %q instead.", code)
+	}
+}
+
+func TestRateLimiterRefillsAndSweeps(t *testing.T) {
+	t.Log("Exhausting one client's burst, then waiting on a fake clock... (expected: allowed again, idle bucket forgotten)")
+	now := time.Now()
+	limiter := NewRateLimiter(2, 2, false)
+	limiter.now = func() time.Time { return now }
+
+	limiter.allow("10.0.0.1")
+	limiter.allow("10.0.0.1")
+	if _, ok := limiter.allow("10.0.0.1"); ok {
+		t.Error("Expected the third request in a burst of 2 to be limited, but it was allowed.")
+	}
+	if _, ok := limiter.allow("10.0.0.2"); !ok {
+		t.Error("Expected another client to be allowed, but it was limited.")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if _, ok := limiter.allow("10.0.0.1"); !ok {
+		t.Error("Expected a request after a token refilled to be allowed, but it was limited.")
+	}
+
+	now = now.Add(time.Minute)
+	limiter.allow("10.0.0.3")
+	if n := len(limiter.buckets); n != 1 {
+		t.Errorf("Expected the idle clients' buckets to be forgotten, but %d buckets remain.", n)
+	}
+}
+
+func TestRateLimiterClientIP(t *testing.T) {
+	t.Log("Reading the client IP with and without trusting X-Forwarded-For... (expected: 203.0.113.7, then 192.0.2.1)")
+	req := httptest.NewRequest(http.MethodGet, "/score", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+
+	if ip := NewRateLimiter(1, 1, true).clientIP(req); ip != "203.0.113.7" {
+		t.Errorf("Expected 203.0.113.7, but it was %q instead.", ip)
+	}
+	if ip := NewRateLimiter(1, 1, false).clientIP(req); ip != "192.0.2.1" {
+		t.Errorf("Expected 192.0.2.1, but it was %q instead.", ip)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ErrGameStarted is returned by SetRules once a ball has been rolled.
+var ErrGameStarted = errors.New("game already started")
+
+// Rules describes the scoring of a bowling variant. A roll records the
+// points a ball knocked down, which in ten-pin is simply the number of pins.
+//
+// A strike clears the rack with the first ball of a frame and scores the
+// rack plus the next two balls. A spare clears it with the second ball and
+// scores the rack plus the next ball. Any other frame, including one cleared
+// by a third ball, scores the points knocked down. The tenth frame has three
+// balls after a strike or spare and BallsPerFrame balls otherwise.
+type Rules struct {
+	// Name identifies the ruleset when a game is saved.
+	Name string `json:"name"`
+
+	// PinValues holds the points each pin on a full rack is worth.
+	PinValues []int `json:"pin_values"`
+
+	// BallsPerFrame is the number of balls each of frames one to nine
+	// allows: 2 or 3.
+	BallsPerFrame int `json:"balls_per_frame"`
+}
+
+// TenPin is the ruleset of standard ten-pin bowling, used by default.
+var TenPin = Rules{
+	Name:          "ten-pin",
+	PinValues:     []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+	BallsPerFrame: 2,
+}
+
+// FivePin is the ruleset of Canadian five-pin bowling, whose pins are worth
+// 2-3-5-3-2 for 15 points a rack, with three balls a frame.
+var FivePin = Rules{
+	Name:          "five-pin",
+	PinValues:     []int{2, 3, 5, 3, 2},
+	BallsPerFrame: 3,
+}
+
+// rulesets maps the name of every predefined ruleset to it.
+var rulesets = map[string]Rules{
+	TenPin.Name:  TenPin,
+	FivePin.Name: FivePin,
+}
+
+// RackValue returns the points scored by knocking down a full rack.
+func (r Rules) RackValue() int {
+	sum := 0
+	for _, value := range r.PinValues {
+		sum += value
+	}
+	return sum
+}
+
+// maxBalls returns the most balls a game can take: BallsPerFrame in each of
+// the first nine frames and three in the tenth.
+func (r Rules) maxBalls() int {
+	return (framesPerGame-1)*r.BallsPerFrame + 3
+}
+
+// maxPins is the most pins a rack can hold.
+const maxPins = 10
+
+// valid reports whether the rules describe a playable game.
+func (r Rules) valid() bool {
+	return r.RackValue() > 0 && len(r.PinValues) <= maxPins && (r.BallsPerFrame == 2 || r.BallsPerFrame == 3)
+}
+
+// uniform reports whether every pin is worth the same.
+func (r Rules) uniform() bool {
+	for _, value := range r.PinValues {
+		if value != r.PinValues[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// WithRules makes a game follow rules instead of TenPin. Rules that aren't
+// valid, with no pins, more than maxPins or a BallsPerFrame other than 2 or
+// 3, leave the game ten-pin.
+func WithRules(rules Rules) GameOption {
+	return func(gm *Game) {
+		if rules.valid() {
+			gm.setRules(rules)
+		}
+	}
+}
+
+// setRules switches the game to rules, sizing its rolls to match and
+// reusing their storage when it is big enough; the caller must hold gm.mu
+// for writing and no rolls may have been made.
+func (gm *Game) setRules(rules Rules) {
+	gm.rules = rules
+	gm.rack = rules.RackValue()
+	if n := rules.maxBalls(); cap(gm.rolls) >= n {
+		gm.rolls = gm.rolls[:n]
+		clear(gm.rolls)
+	} else {
+		gm.rolls = make([]int, n)
+	}
+	gm.invalidateScore()
+}
+
+// Rules returns the ruleset the game is scored by.
+func (gm *Game) Rules() Rules {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.rules
+}
+
+// SetRules switches the game to rules, with noTap as in WithNoTap. Rules can
+// only change before the first ball; after that SetRules returns
+// ErrGameStarted. Rules that aren't valid leave the game ten-pin.
+func (gm *Game) SetRules(rules Rules, noTap int) error {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	if gm.current > 0 {
+		return ErrGameStarted
+	}
+	gm.setRules(TenPin)
+	WithRules(rules)(gm)
+	gm.noTap = 0
+	WithNoTap(noTap)(gm)
+	return nil
+}
+
+// knockable reports whether the next ball can score exactly pins; the caller
+// must hold gm.mu. Rolls record points rather than which pins fell, so every
+// set of pins the frame's earlier balls could have left standing is tried.
+func (gm *Game) knockable(pins int) bool {
+	values := gm.rules.PinValues
+	if gm.rules.uniform() {
+		return pins%values[0] == 0
+	}
+	frame, start := gm.cursor()
+	full := uint(1)<<len(values) - 1
+	standing, left := map[uint]bool{full: true}, gm.rack
+	for _, knocked := range gm.rolls[start:gm.current] {
+		standing = knockDown(values, standing, knocked)
+		if left -= knocked; left == 0 && frame == framesPerGame-1 {
+			standing, left = map[uint]bool{full: true}, gm.rack
+		}
+	}
+	return len(knockDown(values, standing, pins)) > 0
+}
+
+// knockDown returns every set of pins, as bitmasks over values, that a ball
+// scoring pins could leave from one of the sets in standing.
+func knockDown(values []int, standing map[uint]bool, pins int) map[uint]bool {
+	after := make(map[uint]bool)
+	for set := range standing {
+		for fallen := set; ; fallen = (fallen - 1) & set {
+			sum := 0
+			for pin, value := range values {
+				if fallen&(1<<pin) != 0 {
+					sum += value
+				}
+			}
+			if sum == pins {
+				after[set&^fallen] = true
+			}
+			if fallen == 0 {
+				break
+			}
+		}
+	}
+	return after
+}
+
+// endpoint handlers:
+
+// RulesHandler handles the "PATCH /games/{id}/rules" endpoint, which sets the
+// rules of a game that hasn't started from {"ruleset": "five-pin", "no_tap":
+// N}. The ruleset defaults to ten-pin.
+func RulesHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		var config struct {
+			Ruleset string `json:"ruleset"`
+			NoTap   int    `json:"no_tap"`
+		}
+		if !requireJSON(w, r) {
+			return
+		}
+		if err := decodeBody(w, r, &config); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+			return
+		}
+		if config.Ruleset == "" {
+			config.Ruleset = TenPin.Name
+		}
+		rules, ok := rulesets[config.Ruleset]
+		if !ok {
+			writeJSONError(w, http.StatusBadRequest, "invalid_rules", "ruleset must be ten-pin or five-pin")
+			return
+		}
+		if config.NoTap < 0 || config.NoTap >= rules.RackValue() {
+			writeJSONError(w, http.StatusBadRequest, "invalid_no_tap", "no_tap must be less than a full rack")
+			return
+		}
+
+		if err := gm.SetRules(rules, config.NoTap); err != nil {
+			writeGameError(w, err)
+			return
+		}
+		json.NewEncoder(w).Encode(config)
+	}
+}
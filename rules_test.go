@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFivePinPerfectGame(t *testing.T) {
+	t.Log("Rolling twelve five-pin strikes... (expected score: 450)")
+	game := NewGame(WithRules(FivePin))
+	game.rollMany(12, 15)
+
+	if score := game.Score(); score != 450 {
+		t.Errorf("Expected score of 450, but it was %d instead.", score)
+	}
+	if !game.IsComplete() {
+		t.Error("Expected the game to be complete, but it wasn't.")
+	}
+}
+
+func TestFivePinMixedGame(t *testing.T) {
+	t.Log("Rolling a mixed five-pin game... (expected score: 175)")
+	game := NewGame(WithRules(FivePin))
+	rolls := []int{15, 10, 5, 5, 5, 3, 8, 7, 0, 0, 0, 15, 7, 3, 5, 13, 2, 2, 3, 5, 15, 10, 5}
+	if err := game.RollAll(rolls); err != nil {
+		t.Fatalf("Expected the rolls to be accepted, but got %v.", err)
+	}
+
+	want := []int{30, 50, 63, 78, 78, 103, 118, 135, 145, 175}
+	if frames := game.FrameScores(); !reflect.DeepEqual(frames, want) {
+		t.Errorf("Expected frames of %v, but they were %v instead.", want, frames)
+	}
+	if score := game.Score(); score != 175 {
+		t.Errorf("Expected score of 175, but it was %d instead.", score)
+	}
+	if !game.IsComplete() {
+		t.Error("Expected the game to be complete, but it wasn't.")
+	}
+}
+
+func TestFivePinFrameLimits(t *testing.T) {
+	t.Log("Rolling 10 then 6 in a five-pin frame, then 16... (expected: overfill, then invalid pin count)")
+	game := NewGame(WithRules(FivePin))
+	game.Roll(10)
+
+	if err := game.Roll(6); !errors.Is(err, ErrFrameOverfill) {
+		t.Errorf("Expected ErrFrameOverfill, but got %v instead.", err)
+	}
+	if err := game.Roll(16); !errors.Is(err, ErrInvalidPinCount) {
+		t.Errorf("Expected ErrInvalidPinCount, but got %v instead.", err)
+	}
+	if ball := game.BallInFrame(); ball != 2 {
+		t.Errorf("Expected to be on ball 2, but it was ball %d instead.", ball)
+	}
+}
+
+func TestFivePinUnreachableCounts(t *testing.T) {
+	tests := []struct {
+		name   string
+		before []int
+		pins   int
+	}{
+		{"a first ball of 1", nil, 1},
+		{"a first ball of 14", nil, 14},
+		{"a 1 after 13", []int{13}, 1},
+		{"a 4 after 10", []int{10}, 4},
+		{"a 1 after 10 and 2", []int{10, 2}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Rolling %s in a five-pin frame... (expected: ErrInvalidPinCount, game untouched)", tt.name)
+			game := NewGame(WithRules(FivePin))
+			if err := game.RollAll(tt.before); err != nil {
+				t.Fatalf("Expected the earlier rolls to be accepted, but got %v.", err)
+			}
+
+			if err := game.Roll(tt.pins); !errors.Is(err, ErrInvalidPinCount) {
+				t.Errorf("Expected ErrInvalidPinCount, but got %v instead.", err)
+			}
+			if rolls := game.Rolls(); len(rolls) != len(tt.before) {
+				t.Errorf("Expected %d rolls, but they were %v instead.", len(tt.before), rolls)
+			}
+		})
+	}
+}
+
+func TestFivePinReachableCounts(t *testing.T) {
+	t.Log("Rolling 10, 3 and 2 in a five-pin frame, then 13 and 2 in the next... (expected: all accepted)")
+	game := NewGame(WithRules(FivePin))
+
+	if err := game.RollAll([]int{10, 3, 2, 13, 2}); err != nil {
+		t.Errorf("Expected the rolls to be accepted, but got %v.", err)
+	}
+	if score := game.Score(); score != 30 {
+		t.Errorf("Expected score of 30, but it was %d instead.", score)
+	}
+}
+
+func TestRulesJSONRoundTrip(t *testing.T) {
+	t.Log("Saving and restoring a five-pin game... (expected: same rules and score)")
+	game := NewGame(WithRules(FivePin))
+	game.rollMany(3, 15)
+
+	data, err := json.Marshal(game)
+	if err != nil {
+		t.Fatalf("Expected to marshal the game, but got %v.", err)
+	}
+	restored := NewGame()
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("Expected to unmarshal the game, but got %v.", err)
+	}
+	if rules := restored.Rules(); rules.Name != FivePin.Name {
+		t.Errorf("Expected five-pin rules, but they were %+v instead.", rules)
+	}
+	if score := restored.Score(); score != game.Score() {
+		t.Errorf("Expected score of %d, but it was %d instead.", game.Score(), score)
+	}
+}
+
+func TestWithRulesIgnoresInvalid(t *testing.T) {
+	t.Log("Creating a game with one ball a frame... (expected: ten-pin rules)")
+	game := NewGame(WithRules(Rules{PinValues: []int{1}, BallsPerFrame: 1}))
+
+	if rules := game.Rules(); rules.Name != TenPin.Name {
+		t.Errorf("Expected ten-pin rules, but they were %+v instead.", rules)
+	}
+}
+
+func TestRulesHandlerBeforePlay(t *testing.T) {
+	t.Log("PATCHing five-pin rules onto a new stored game... (expected status: 200, five-pin scoring)")
+	store := NewGameStore()
+	id, game, _ := store.Create()
+	rec := httptest.NewRecorder()
+	GameHandler(store)(rec, httptest.NewRequest(http.MethodPatch, "/games/"+id+"/rules", strings.NewReader(`{"ruleset": "five-pin"}`)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status of 200, but it was %d instead.", rec.Code)
+	}
+	if rules := game.Rules(); rules.Name != FivePin.Name {
+		t.Errorf("Expected five-pin rules, but they were %+v instead.", rules)
+	}
+	game.rollMany(12, 15)
+	if score := game.Score(); score != 450 {
+		t.Errorf("Expected score of 450, but it was %d instead.", score)
+	}
+}
+
+func TestRulesHandlerMidGame(t *testing.T) {
+	t.Log("PATCHing no-tap rules after a roll... (expected status: 409, rules unchanged)")
+	store := NewGameStore()
+	id, game, _ := store.Create()
+	game.Roll(9)
+	rec := httptest.NewRecorder()
+	GameHandler(store)(rec, httptest.NewRequest(http.MethodPatch, "/games/"+id+"/rules", strings.NewReader(`{"no_tap": 9}`)))
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected status of 409, but it was %d instead.", rec.Code)
+	}
+	if code := decodeErrorCode(t, rec); code != "game_started" {
+		t.Errorf("Expected error code game_started, but it was %q instead.", code)
+	}
+	if score := game.Score(); score != 9 {
+		t.Errorf("Expected the 9 to stay a 9, but the score was %d.", score)
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ScoreCard renders the game as an ASCII scorecard, with each frame's balls
+// marked as X, / or a pin count, and the running total beneath them. A named
+// game's card is headed by the player's name.
+func (gm *Game) ScoreCard() string {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	frames, totals := gm.frames(), gm.frameScores()
+
+	var border, numbers, balls, running strings.Builder
+	for frame := 0; frame < framesPerGame; frame++ {
+		boxes := gm.rules.BallsPerFrame
+		if frame == framesPerGame-1 {
+			boxes = 3
+		}
+		width := 2*boxes + 1
+
+		symbols := make([]string, boxes)
+		for x := range symbols {
+			symbols[x] = " "
+		}
+		if frame < len(frames) {
+			marked := marks(frames[frame], gm.rack)
+			if frame < framesPerGame-1 && len(marked) == 1 && marked[0] == "X" {
+				marked = append(make([]string, boxes-1), "X")
+				for x := range marked[:boxes-1] {
+					marked[x] = " "
+				}
+			}
+			copy(symbols, marked)
+		}
+		total := ""
+		if frame < len(totals) {
+			total = strconv.Itoa(totals[frame])
+		}
+
+		border.WriteString("+" + strings.Repeat("-", width))
+		fmt.Fprintf(&numbers, "|%*d%*s", width/2+1, frame+1, width-width/2-1, "")
+		fmt.Fprintf(&balls, "| %s ", strings.Join(symbols, " "))
+		fmt.Fprintf(&running, "|%*s ", width-1, total)
+	}
+	border.WriteString("+\n")
+
+	heading := ""
+	if gm.name != "" {
+		heading = gm.name + "\n"
+	}
+	return heading + border.String() +
+		numbers.String() + "|\n" +
+		border.String() +
+		balls.String() + "|\n" +
+		running.String() + "|\n" +
+		border.String()
+}
+
+// scoreCardHTML renders a game as an HTML table of frames, marks and running
+// totals.
+var scoreCardHTML = template.Must(template.New("scorecard").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Scorecard</title></head>
+<body>
+<table class="scorecard">
+{{with .Name}}<caption>{{.}}</caption>
+{{end}}<tr>{{range .Frames}}<th>{{.Number}}</th>{{end}}<th>Score</th></tr>
+<tr>{{range .Frames}}<td class="marks">{{.Marks}}</td>{{end}}<td rowspan="2">{{.Score}}</td></tr>
+<tr>{{range .Frames}}<td class="total">{{.Total}}</td>{{end}}</tr>
+</table>
+</body>
+</html>
+`))
+
+// WriteScoreCardHTML writes the game to w as an HTML page holding the same
+// scorecard as ScoreCard, laid out as a table.
+func (gm *Game) WriteScoreCardHTML(w io.Writer) error {
+	type htmlFrame struct {
+		Number int
+		Marks  string
+		Total  string
+	}
+	gm.mu.RLock()
+	frames, totals := gm.frames(), gm.frameScores()
+	card := struct {
+		Name   string
+		Frames []htmlFrame
+		Score  int
+	}{
+		Name:   gm.name,
+		Frames: make([]htmlFrame, framesPerGame),
+		Score:  gm.score(),
+	}
+	for x := range card.Frames {
+		card.Frames[x].Number = x + 1
+		if x < len(frames) {
+			card.Frames[x].Marks = strings.Join(marks(frames[x], gm.rack), " ")
+		}
+		if x < len(totals) {
+			card.Frames[x].Total = strconv.Itoa(totals[x])
+		}
+	}
+	gm.mu.RUnlock()
+
+	return scoreCardHTML.Execute(w, card)
+}
+
+// svgBoxWidth and svgBoxHeight size the box of each of the first nine frames
+// on an SVG scorecard; the tenth is half as wide again for its third ball.
+const (
+	svgBoxWidth  = 60
+	svgBoxHeight = 60
+)
+
+// scoreCardSVG renders a game as an SVG image of frame boxes holding each
+// frame's marks above its running total.
+var scoreCardSVG = template.Must(template.New("scorecard.svg").Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}" font-family="sans-serif" text-anchor="middle">
+<rect width="{{.Width}}" height="{{.Height}}" fill="white"/>
+{{with .Name}}<text x="4" y="18" text-anchor="start" font-size="16">{{.}}</text>
+{{end}}{{range .Frames}}<g transform="translate({{.X}} {{$.Top}})">
+<rect width="{{.Width}}" height="{{$.BoxHeight}}" fill="none" stroke="black"/>
+<text x="{{.Middle}}" y="22" font-size="14">{{.Marks}}</text>
+<text x="{{.Middle}}" y="50" font-size="16">{{.Total}}</text>
+</g>
+{{end}}</svg>
+`))
+
+// WriteScoreCardSVG writes the game to w as an SVG image of the same
+// scorecard as ScoreCard, headed by the player's name if it has one.
+func (gm *Game) WriteScoreCardSVG(w io.Writer) error {
+	type svgFrame struct {
+		X, Width, Middle int
+		Marks, Total     string
+	}
+	gm.mu.RLock()
+	frames, totals := gm.frames(), gm.frameScores()
+	card := struct {
+		Name                          string
+		Frames                        []svgFrame
+		Width, Height, Top, BoxHeight int
+	}{
+		Name:      gm.name,
+		Frames:    make([]svgFrame, framesPerGame),
+		BoxHeight: svgBoxHeight,
+	}
+	for x := range card.Frames {
+		f := &card.Frames[x]
+		f.X, f.Width = card.Width, svgBoxWidth
+		if x == framesPerGame-1 {
+			f.Width = svgBoxWidth * 3 / 2
+		}
+		f.Middle = f.Width / 2
+		card.Width += f.Width
+		if x < len(frames) {
+			f.Marks = strings.Join(marks(frames[x], gm.rack), " ")
+		}
+		if x < len(totals) {
+			f.Total = strconv.Itoa(totals[x])
+		}
+	}
+	gm.mu.RUnlock()
+
+	if card.Name != "" {
+		card.Top = 24
+	}
+	card.Height = card.Top + svgBoxHeight
+	return scoreCardSVG.Execute(w, card)
+}
+
+// marks returns the scorecard symbol for each ball of a single frame, where
+// rack is the value of a full rack. Only the second ball at a rack can be a
+// spare; a five-pin rack cleared by its third ball is written as a number.
+// Pins are set up afresh once a rack is cleared, which only happens
+// mid-frame in the tenth.
+func marks(balls []int, rack int) []string {
+	symbols := make([]string, len(balls))
+	standing, thrown := rack, 0
+	for x, pins := range balls {
+		thrown++
+		switch {
+		case pins == rack && thrown == 1:
+			symbols[x] = "X"
+		case pins == standing && thrown == 2:
+			symbols[x] = "/"
+		case pins == 0:
+			symbols[x] = "-"
+		default:
+			symbols[x] = strconv.Itoa(pins)
+		}
+		standing -= pins
+		if standing == 0 {
+			standing, thrown = rack, 0
+		}
+	}
+	return symbols
+}
+
+// endpoint handlers:
+
+// ScoreCardHandler handles the "GET /scorecard" endpoint. The scorecard is
+// plain text unless the Accept header asks for application/json, which gets
+// the notation, running totals and score, or text/html.
+func ScoreCardHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		w.Header().Set("Vary", "Accept")
+		switch negotiate(r, "text/plain", "application/json", "text/html") {
+		case "application/json":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(cardOf(gm))
+		case "text/html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			gm.WriteScoreCardHTML(w)
+		default:
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			fmt.Fprint(w, gm.ScoreCard())
+		}
+	}
+}
+
+// ScoreCardSVGHandler handles the "GET /games/{id}/scorecard.svg" endpoint,
+// returning the scorecard as an SVG image.
+func ScoreCardSVGHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/svg+xml")
+		gm.WriteScoreCardSVG(w)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestScoreCard(t *testing.T) {
+	t.Log("Rendering X 7/ 9- X -8 8/ -6 X X X81... (expected: matches testdata/scorecard.golden)")
+	game := NewGame()
+	game.rollMixedGame()
+
+	golden := filepath.Join("testdata", "scorecard.golden")
+	got := game.ScoreCard()
+	if *update {
+		os.WriteFile(golden, []byte(got), 0644)
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Expected to read %s, but got %v.", golden, err)
+	}
+	if got != string(want) {
+		t.Errorf("Expected scorecard:\n%s\nbut it was:\n%s", want, got)
+	}
+}
+
+func TestScoreCardHandler(t *testing.T) {
+	t.Log("GETting the scorecard... (expected Content-Type: text/plain)")
+	game := NewGame()
+	game.rollStrike()
+	rec := httptest.NewRecorder()
+	ScoreCardHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/scorecard", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status of 200, but it was %d instead.", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("Expected Content-Type of text/plain, but it was %q instead.", ct)
+	}
+	if body := rec.Body.String(); body != game.ScoreCard() {
+		t.Errorf("Expected the scorecard as the body, but it was:\n%s", body)
+	}
+}
+
+func TestScoreCardHandlerNegotiation(t *testing.T) {
+	tests := []struct {
+		accept      string
+		contentType string
+		prefix      string
+	}{
+		{"", "text/plain; charset=utf-8", "+-----"},
+		{"text/html", "text/html; charset=utf-8", "<!DOCTYPE html>"},
+		{"application/json", "application/json", `{"name"`},
+		{"text/html,application/xhtml+xml,*/*;q=0.8", "text/html; charset=utf-8", "<!DOCTYPE html>"},
+		{"application/json, text/html", "application/json", `{"name"`},
+		{"text/html;q=0.5, */*", "text/plain; charset=utf-8", "+-----"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			t.Logf("GETting the scorecard accepting %q... (expected Content-Type: %s)", tt.accept, tt.contentType)
+			game := NewGame()
+			game.rollMixedGame()
+			req := httptest.NewRequest(http.MethodGet, "/scorecard", nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			ScoreCardHandler(game)(rec, req)
+
+			if ct := rec.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Errorf("Expected Content-Type of %s, but it was %q instead.", tt.contentType, ct)
+			}
+			if body := rec.Body.String(); !strings.HasPrefix(body, tt.prefix) {
+				t.Errorf("Expected the body to start with %q, but it was:\n%s", tt.prefix, body)
+			}
+		})
+	}
+}
+
+func TestWriteScoreCardHTML(t *testing.T) {
+	t.Log("Rendering X 7/ 9- X -8 8/ -6 X X X81 as HTML... (expected: marks, running totals and 167)")
+	game := NewGame()
+	game.rollMixedGame()
+	var b strings.Builder
+	game.WriteScoreCardHTML(&b)
+
+	for _, want := range []string{`<td class="marks">7 /</td>`, `<td class="marks">X 8 1</td>`, `<td class="total">20</td>`, `<td rowspan="2">167</td>`} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Expected the HTML to contain %s, but it was:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestScoreCardFivePinThirdBallClear(t *testing.T) {
+	t.Log("Rendering a five-pin frame of 5 5 5... (expected: the third ball written as 5, not /)")
+	game := NewGame(WithRules(FivePin))
+	game.RollAll([]int{5, 5, 5})
+	var b strings.Builder
+	game.WriteScoreCardHTML(&b)
+
+	if card := game.ScoreCard(); !strings.Contains(card, "| 5 5 5 |") {
+		t.Errorf("Expected the scorecard to show 5 5 5, but it was:\n%s", card)
+	}
+	if !strings.Contains(b.String(), `<td class="marks">5 5 5</td>`) {
+		t.Errorf("Expected the HTML to show 5 5 5, but it was:\n%s", b.String())
+	}
+}
+
+func TestScoreCardSVG(t *testing.T) {
+	t.Log("GETting /games/{id}/scorecard.svg for X 7/ 9- X -8 8/ -6 X X X81... (expected: an SVG with every running total)")
+	store := NewGameStore()
+	id, game, _ := store.Create()
+	game.rollMixedGame()
+
+	rec := httptest.NewRecorder()
+	GameHandler(store)(rec, httptest.NewRequest(http.MethodGet, "/games/"+id+"/scorecard.svg", nil))
+
+	if contentType := rec.Header().Get("Content-Type"); contentType != "image/svg+xml" {
+		t.Errorf("Expected a Content-Type of image/svg+xml, but it was %q instead.", contentType)
+	}
+	svg := rec.Body.String()
+	if !strings.HasPrefix(svg, "<svg ") {
+		t.Errorf("Expected an SVG image, but it was:\n%s", svg)
+	}
+	for _, total := range []string{"20", "39", "48", "66", "74", "84", "90", "120", "148", "167"} {
+		if want := `font-size="16">` + total + `</text>`; !strings.Contains(svg, want) {
+			t.Errorf("Expected the SVG to contain %s, but it was:\n%s", want, svg)
+		}
+	}
+}
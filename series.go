@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrSeriesNotFound is returned when a request references an unknown series
+// ID.
+var ErrSeriesNotFound = errors.New("series not found")
+
+// seriesGame is a game in a series. The game itself is kept so a series
+// still adds up after its games expire from the store.
+type seriesGame struct {
+	id string
+	gm *Game
+}
+
+// SeriesGame is the score of one game in a series.
+type SeriesGame struct {
+	ID    string `json:"id"`
+	Score int    `json:"score"`
+}
+
+// Series is a set of games bowled as one series, usually three, and their
+// combined total.
+type Series struct {
+	ID    string       `json:"id"`
+	Games []SeriesGame `json:"games"`
+	Total int          `json:"total"`
+}
+
+// CreateSeries groups the stored games with the given IDs into a new series
+// and returns its ID. It returns an error wrapping ErrGameNotFound, creating
+// nothing, if any of the games is not in the store.
+func (s *GameStore) CreateSeries(gameIDs ...string) (string, error) {
+	games, err := s.seriesGames(gameIDs)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, err := s.newID(func(id string) bool { return s.series[id] != nil })
+	if err != nil {
+		return "", err
+	}
+	s.series[id] = append(make([]seriesGame, 0, len(games)), games...)
+	return id, nil
+}
+
+// AddToSeries adds the stored game gameID to the series seriesID. It returns
+// ErrSeriesNotFound or an error wrapping ErrGameNotFound if either is
+// unknown.
+func (s *GameStore) AddToSeries(seriesID, gameID string) error {
+	games, err := s.seriesGames([]string{gameID})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.series[seriesID]; !ok {
+		return ErrSeriesNotFound
+	}
+	s.series[seriesID] = append(s.series[seriesID], games...)
+	return nil
+}
+
+// Series returns the score of each game in the series id and their total, or
+// ErrSeriesNotFound.
+func (s *GameStore) Series(id string) (Series, error) {
+	s.mu.Lock()
+	games, ok := s.series[id]
+	s.mu.Unlock()
+	if !ok {
+		return Series{}, ErrSeriesNotFound
+	}
+
+	series := Series{ID: id, Games: make([]SeriesGame, len(games))}
+	for x, g := range games {
+		series.Games[x] = SeriesGame{ID: g.id, Score: g.gm.Score()}
+		series.Total += series.Games[x].Score
+	}
+	return series, nil
+}
+
+// seriesGames looks up the games with the given IDs.
+func (s *GameStore) seriesGames(ids []string) ([]seriesGame, error) {
+	games := make([]seriesGame, len(ids))
+	for x, id := range ids {
+		gm, err := s.Get(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, id)
+		}
+		games[x] = seriesGame{id: id, gm: gm}
+	}
+	return games, nil
+}
+
+// endpoint handlers:
+
+// CreateSeriesHandler handles the "POST /series" endpoint, which groups the
+// games in {"games": ["id", ...]} into a new series.
+func CreateSeriesHandler(store *GameStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		var body struct {
+			Games []string `json:"games"`
+		}
+		if !requireJSON(w, r) {
+			return
+		}
+		if err := decodeBody(w, r, &body); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+			return
+		}
+
+		id, err := store.CreateSeries(body.Games...)
+		if err != nil {
+			writeGameError(w, err)
+			return
+		}
+		series, _ := store.Series(id)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(series)
+	}
+}
+
+// SeriesHandler handles the "GET /series/{id}" endpoint and the
+// "POST /series/{id}/games" endpoint, which adds the game in {"game": "id"}
+// to the series.
+func SeriesHandler(store *GameStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/series/"), "/")
+		switch {
+		case action == "" && r.Method == http.MethodGet:
+		case action == "games" && r.Method == http.MethodPost:
+			var body struct {
+				Game string `json:"game"`
+			}
+			if !requireJSON(w, r) {
+				return
+			}
+			if err := decodeBody(w, r, &body); err != nil {
+				writeJSONError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+				return
+			}
+			if err := store.AddToSeries(id, body.Game); err != nil {
+				writeGameError(w, err)
+				return
+			}
+		case action == "":
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		case action == "games":
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		default:
+			writeJSONError(w, http.StatusNotFound, "not_found", "Not found")
+			return
+		}
+
+		series, err := store.Series(id)
+		if err != nil {
+			writeGameError(w, err)
+			return
+		}
+		json.NewEncoder(w).Encode(series)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSeries(t *testing.T) {
+	t.Log("Grouping games of 300, 167 and 20 into a series... (expected total: 487)")
+	store := NewGameStore()
+	perfectID, perfect, _ := store.Create()
+	perfect.rollMany(12, 10)
+	mixedID, mixed, _ := store.Create()
+	mixed.rollMixedGame()
+	onesID, ones, _ := store.Create()
+	ones.rollMany(20, 1)
+
+	id, err := store.CreateSeries(perfectID, mixedID)
+	if err != nil {
+		t.Fatalf("Expected the series to be created, but got %v.", err)
+	}
+	if err := store.AddToSeries(id, onesID); err != nil {
+		t.Fatalf("Expected the game to be added, but got %v.", err)
+	}
+
+	series, err := store.Series(id)
+	if err != nil {
+		t.Fatalf("Expected the series to be found, but got %v.", err)
+	}
+	if len(series.Games) != 3 || series.Total != 300+167+20 {
+		t.Errorf("Expected 3 games totalling 487, but the series was %+v.", series)
+	}
+}
+
+func TestSeriesUnknownGame(t *testing.T) {
+	t.Log("Creating a series with an unknown game, then adding one... (expected: ErrGameNotFound both times)")
+	store := NewGameStore()
+	gameID, _, _ := store.Create()
+	if _, err := store.CreateSeries(gameID, "nope"); !errors.Is(err, ErrGameNotFound) {
+		t.Errorf("Expected ErrGameNotFound, but got %v instead.", err)
+	}
+
+	id, _ := store.CreateSeries(gameID)
+	if err := store.AddToSeries(id, "nope"); !errors.Is(err, ErrGameNotFound) {
+		t.Errorf("Expected ErrGameNotFound, but got %v instead.", err)
+	}
+	if err := store.AddToSeries("nope", gameID); !errors.Is(err, ErrSeriesNotFound) {
+		t.Errorf("Expected ErrSeriesNotFound, but got %v instead.", err)
+	}
+}
+
+func TestSeriesHandlers(t *testing.T) {
+	t.Log("POSTing a series of two games, adding a third and GETting it... (expected total: 60)")
+	store := NewGameStore()
+	var ids []string
+	for x := 0; x < 3; x++ {
+		id, game, _ := store.Create()
+		game.rollMany(20, 1)
+		ids = append(ids, id)
+	}
+
+	rec := httptest.NewRecorder()
+	CreateSeriesHandler(store)(rec, httptest.NewRequest(http.MethodPost, "/series", strings.NewReader(`{"games": ["`+ids[0]+`", "`+ids[1]+`"]}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status of 201, but it was %d instead.", rec.Code)
+	}
+	var created Series
+	json.NewDecoder(rec.Body).Decode(&created)
+
+	rec = httptest.NewRecorder()
+	SeriesHandler(store)(rec, httptest.NewRequest(http.MethodPost, "/series/"+created.ID+"/games", strings.NewReader(`{"game": "`+ids[2]+`"}`)))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status of 200, but it was %d instead.", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	SeriesHandler(store)(rec, httptest.NewRequest(http.MethodGet, "/series/"+created.ID, nil))
+	var got Series
+	json.NewDecoder(rec.Body).Decode(&got)
+	if len(got.Games) != 3 || got.Total != 60 {
+		t.Errorf("Expected 3 games totalling 60, but the series was %+v.", got)
+	}
+
+	rec = httptest.NewRecorder()
+	SeriesHandler(store)(rec, httptest.NewRequest(http.MethodGet, "/series/nope", nil))
+	if code := decodeErrorCode(t, rec); rec.Code != http.StatusNotFound || code != "series_not_found" {
+		t.Errorf("Expected a 404 series_not_found, but got %d %q.", rec.Code, code)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Simulate plays out a full game of random but legal rolls drawn from rng,
+// each knocking down anywhere from none to all of the pins left standing.
+func Simulate(rng *rand.Rand, opts ...GameOption) *Game {
+	gm := NewGame(opts...)
+	playOut(rng, gm)
+	return gm
+}
+
+// playOut finishes gm with random but legal rolls drawn from rng, as in
+// Simulate. The rolls skip Roll's bookkeeping, so they count toward no
+// metrics and fire no completion hook or score events.
+func playOut(rng *rand.Rand, gm *Game) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	for !gm.isComplete() {
+		gm.roll(rng.Intn(gm.standingPins() + 1))
+	}
+}
+
+// endpoint handlers:
+
+// SimulateHandler handles the "POST /simulate" endpoint, returning the
+// scorecard of a simulated game. The optional "seed" query parameter makes
+// the game reproducible.
+func SimulateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+		rng, err := seededRand(r)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid_query", "seed must be an integer")
+			return
+		}
+
+		gm := Simulate(rng)
+		json.NewEncoder(w).Encode(cardOf(gm))
+	}
+}
+
+// seededRand returns a random source seeded by the request's "seed" query
+// parameter, or by the current time if it has none.
+func seededRand(r *http.Request) (*rand.Rand, error) {
+	seed := time.Now().UnixNano()
+	if s := r.URL.Query().Get("seed"); s != "" {
+		var err error
+		if seed, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	return rand.New(rand.NewSource(seed)), nil
+}
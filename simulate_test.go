@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSimulate(t *testing.T) {
+	t.Log("Simulating a game seeded with 42... (expected: complete, score 58)")
+	game := Simulate(rand.New(rand.NewSource(42)))
+
+	if !game.IsComplete() {
+		t.Error("Expected the game to be complete, but it was not.")
+	}
+	if score := game.Score(); score != 58 {
+		t.Errorf("Expected score of 58, but it was %d instead.", score)
+	}
+}
+
+func TestSimulateHandler(t *testing.T) {
+	t.Log("POSTing /simulate?seed=42 twice... (expected: the same 58 game both times)")
+	var cards [2]playerCard
+	for x := range cards {
+		rec := httptest.NewRecorder()
+		SimulateHandler()(rec, httptest.NewRequest(http.MethodPost, "/simulate?seed=42", nil))
+		json.NewDecoder(rec.Body).Decode(&cards[x])
+	}
+
+	if cards[0].Score != 58 || cards[0].Notation != cards[1].Notation {
+		t.Errorf("Expected two identical 58 games, but got %+v and %+v.", cards[0], cards[1])
+	}
+
+	rec := httptest.NewRecorder()
+	SimulateHandler()(rec, httptest.NewRequest(http.MethodPost, "/simulate?seed=abc", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status of 400, but it was %d instead.", rec.Code)
+	}
+}
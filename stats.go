@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// GameStats summarizes how the completed frames of a game were bowled.
+//
+// Every strike and spare mark is counted, including those on the tenth
+// frame's fill balls, so a perfect game has twelve strikes and a tenth frame
+// of X 7/ counts one of each. A frame is open when neither of its first two
+// balls is marked as a strike or spare. Gutters counts every ball that
+// knocked down no pins. Perfect is set once the game is a perfect 300.
+// Clean is set once the game is complete without an open frame. Streak is the
+// run of strikes ending at the last ball, as ConsecutiveStrikes reports it.
+// AveragePinsPerBall covers every ball thrown, as AveragePinsPerBall reports
+// it.
+type GameStats struct {
+	Strikes            int     `json:"strikes"`
+	Spares             int     `json:"spares"`
+	OpenFrames         int     `json:"open_frames"`
+	Gutters            int     `json:"gutters"`
+	Perfect            bool    `json:"perfect"`
+	Clean              bool    `json:"clean"`
+	Streak             int     `json:"streak"`
+	AveragePinsPerBall float64 `json:"average_pins_per_ball"`
+}
+
+// Stats returns the strike, spare, open frame and gutter ball counts over the
+// frames bowled to completion.
+func (gm *Game) Stats() GameStats {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	stats := GameStats{
+		Perfect:            gm.isPerfect(),
+		Clean:              gm.isClean(),
+		Streak:             gm.consecutiveStrikes(),
+		AveragePinsPerBall: gm.averagePinsPerBall(),
+	}
+	for _, balls := range gm.completedFrames() {
+		open := true
+		for x, mark := range marks(balls, gm.rack) {
+			switch mark {
+			case "X":
+				stats.Strikes++
+			case "/":
+				stats.Spares++
+			case "-":
+				stats.Gutters++
+				continue
+			default:
+				continue
+			}
+			if x < 2 {
+				open = false
+			}
+		}
+		if open {
+			stats.OpenFrames++
+		}
+	}
+	return stats
+}
+
+// ConsecutiveStrikes returns how many strikes in a row the game's most recent
+// balls have been: 2 for a double, 3 for a turkey, or 0 if the last ball was
+// not a strike. Strikes on the tenth frame's fill balls extend the run.
+func (gm *Game) ConsecutiveStrikes() int {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.consecutiveStrikes()
+}
+
+// consecutiveStrikes is ConsecutiveStrikes without locking; the caller must
+// hold gm.mu.
+func (gm *Game) consecutiveStrikes() int {
+	streak := 0
+	for _, balls := range gm.frames() {
+		for _, mark := range marks(balls, gm.rack) {
+			if mark != "X" {
+				streak = 0
+				continue
+			}
+			streak++
+		}
+	}
+	return streak
+}
+
+// AveragePinsPerBall returns the mean pins knocked down by the balls thrown
+// so far, including any in a frame still in progress, or 0 before the first
+// ball.
+func (gm *Game) AveragePinsPerBall() float64 {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.averagePinsPerBall()
+}
+
+// averagePinsPerBall is AveragePinsPerBall without locking; the caller must
+// hold gm.mu.
+func (gm *Game) averagePinsPerBall() float64 {
+	if gm.current == 0 {
+		return 0
+	}
+	sum := 0
+	for _, pins := range gm.rolls[:gm.current] {
+		sum += pins
+	}
+	return float64(sum) / float64(gm.current)
+}
+
+// IsPerfect reports whether the game is complete with twelve consecutive
+// strikes, for a score of 300.
+func (gm *Game) IsPerfect() bool {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.isPerfect()
+}
+
+// isPerfect is IsPerfect without locking; the caller must hold gm.mu.
+func (gm *Game) isPerfect() bool {
+	if !gm.isComplete() || gm.current != framesPerGame+2 {
+		return false
+	}
+	for _, pins := range gm.rolls[:gm.current] {
+		if pins != gm.rack {
+			return false
+		}
+	}
+	return true
+}
+
+// BonusBreakdown returns the points the game's strike and spare bonuses have
+// earned so far, over and above the pins knocked down. The tenth frame's fill
+// balls are only counted once, so they add no bonus: a perfect game has 180
+// points of strike bonus on top of its 120 pins.
+func (gm *Game) BonusBreakdown() (strikeBonus, spareBonus int) {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	for throw, frame := 0, 0; frame < framesPerGame-1 && throw < gm.current; frame++ {
+		points, next, _ := gm.frameAt(frame, throw)
+		switch {
+		case gm.isStrike(throw):
+			strikeBonus += points - gm.rack
+		case gm.isSpare(throw):
+			spareBonus += points - gm.rack
+		}
+		throw = next
+	}
+	return strikeBonus, spareBonus
+}
+
+// IsClean reports whether the game is complete with a strike or spare in
+// every frame. A game in progress is never clean, even if no frame has been
+// left open yet.
+func (gm *Game) IsClean() bool {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.isClean()
+}
+
+// isClean is IsClean without locking; the caller must hold gm.mu.
+func (gm *Game) isClean() bool {
+	if !gm.isComplete() {
+		return false
+	}
+	for _, balls := range gm.frames() {
+		if gm.rackMarks(balls)[0] == "open" {
+			return false
+		}
+	}
+	return true
+}
+
+// completedFrames is frames less any frame still in progress; the caller must
+// hold gm.mu.
+func (gm *Game) completedFrames() [][]int {
+	frames := gm.frames()
+	if frame, _ := gm.cursor(); !gm.isComplete() && len(frames) > frame {
+		frames = frames[:frame]
+	}
+	return frames
+}
+
+// endpoint handlers:
+
+// StatsHandler handles the "GET /stats" endpoint.
+func StatsHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		json.NewEncoder(w).Encode(gm.Stats())
+	}
+}
+
+// BonusesHandler handles the "GET /bonuses" endpoint.
+func BonusesHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		strike, spare := gm.BonusBreakdown()
+		response := struct {
+			Strike int `json:"strike"`
+			Spare  int `json:"spare"`
+		}{
+			Strike: strike,
+			Spare:  spare,
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatsPerfectGame(t *testing.T) {
+	t.Log("Rolling all strikes... (expected: 12 strikes, perfect, clean, a streak of 12)")
+	game := NewGame()
+	game.rollMany(12, 10)
+
+	if stats, want := game.Stats(), (GameStats{Strikes: 12, Perfect: true, Clean: true, Streak: 12, AveragePinsPerBall: 10}); stats != want {
+		t.Errorf("Expected stats of %+v, but they were %+v instead.", want, stats)
+	}
+}
+
+func TestStatsAllSpares(t *testing.T) {
+	t.Log("Rolling 9/ in every frame with a 9 fill ball... (expected: 10 spares, clean, nothing else)")
+	game, _ := ParseNotation("9/ 9/ 9/ 9/ 9/ 9/ 9/ 9/ 9/ 9/9")
+
+	if stats, want := game.Stats(), (GameStats{Spares: 10, Clean: true, AveragePinsPerBall: 109.0 / 21}); stats != want {
+		t.Errorf("Expected stats of %+v, but they were %+v instead.", want, stats)
+	}
+}
+
+func TestStatsMixedGame(t *testing.T) {
+	t.Log("Rolling X 7/ 9- X -8 8/ -6 X X X81... (expected: 5 strikes, 2 spares, 3 open, 3 gutters)")
+	game := NewGame()
+	game.rollMixedGame()
+
+	want := GameStats{Strikes: 5, Spares: 2, OpenFrames: 3, Gutters: 3, AveragePinsPerBall: 6}
+	if stats := game.Stats(); stats != want {
+		t.Errorf("Expected stats of %+v, but they were %+v instead.", want, stats)
+	}
+}
+
+func TestStatsFivePinThirdBallClear(t *testing.T) {
+	t.Log("Rolling five-pin frames of 5 5 5 and 13 2... (expected: 1 spare, 1 open frame)")
+	game := NewGame(WithRules(FivePin))
+	game.RollAll([]int{5, 5, 5, 13, 2})
+
+	if stats := game.Stats(); stats.Spares != 1 || stats.OpenFrames != 1 {
+		t.Errorf("Expected 1 spare and 1 open frame, but the stats were %+v instead.", stats)
+	}
+}
+
+func TestStatsSkipsFrameInProgress(t *testing.T) {
+	t.Log("Rolling an open frame, then a gutter ball... (expected: 1 open frame, no gutters)")
+	game := NewGame()
+	game.Roll(3)
+	game.Roll(4)
+	game.Roll(0)
+
+	if stats, want := game.Stats(), (GameStats{OpenFrames: 1, AveragePinsPerBall: 7.0 / 3}); stats != want {
+		t.Errorf("Expected stats of %+v, but they were %+v instead.", want, stats)
+	}
+}
+
+func TestAveragePinsPerBall(t *testing.T) {
+	tests := []struct {
+		name  string
+		rolls []int
+		want  float64
+	}{
+		{"empty game", nil, 0},
+		{"a few rolls", []int{10, 7, 3, 9}, 7.25},
+		{"gutter balls", []int{0, 0}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Averaging the pins of %v... (expected: %v)", tt.rolls, tt.want)
+			game := NewGame()
+			game.RollAll(tt.rolls)
+			if got := game.AveragePinsPerBall(); got != tt.want {
+				t.Errorf("Expected an average of %v, but it was %v instead.", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConsecutiveStrikes(t *testing.T) {
+	tests := []struct {
+		name     string
+		notation string
+		want     int
+	}{
+		{"turkey", "7/ X X X", 3},
+		{"double", "X X", 2},
+		{"broken by an open frame", "X X X 3", 0},
+		{"strike after an open frame", "X X 36 X", 1},
+		{"spare breaks the run", "X X 7/", 0},
+		{"fill balls in the tenth", "-- -- -- -- -- -- -- -- X XXX", 4},
+		{"no balls", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Counting the streak after %q... (expected: %d)", tt.notation, tt.want)
+			game, _ := ParseNotation(tt.notation)
+
+			if streak := game.ConsecutiveStrikes(); streak != tt.want {
+				t.Errorf("Expected a streak of %d, but it was %d instead.", tt.want, streak)
+			}
+		})
+	}
+}
+
+func TestIsPerfect(t *testing.T) {
+	t.Log("Classifying a 300 game and a 299 game... (expected: only the 300 is perfect)")
+	perfect := NewGame()
+	perfect.rollMany(12, 10)
+	if perfect.Score() != 300 || !perfect.IsPerfect() {
+		t.Errorf("Expected a perfect 300, but the game scored %d and IsPerfect was %t.", perfect.Score(), perfect.IsPerfect())
+	}
+
+	nearMiss := NewGame()
+	nearMiss.rollMany(11, 10)
+	nearMiss.Roll(9)
+	if nearMiss.Score() != 299 || nearMiss.IsPerfect() {
+		t.Errorf("Expected a 299 that isn't perfect, but the game scored %d and IsPerfect was %t.", nearMiss.Score(), nearMiss.IsPerfect())
+	}
+
+	inProgress := NewGame()
+	inProgress.rollMany(11, 10)
+	if inProgress.IsPerfect() {
+		t.Error("Expected eleven strikes not to be perfect yet, but IsPerfect was true.")
+	}
+}
+
+func TestBonusBreakdown(t *testing.T) {
+	tests := []struct {
+		name          string
+		notation      string
+		strike, spare int
+	}{
+		{"perfect game", "X X X X X X X X X XXX", 180, 0},
+		{"all spares", "9/ 9/ 9/ 9/ 9/ 9/ 9/ 9/ 9/ 9/9", 0, 81},
+		{"mixed game", "X 7/ 9- X -8 8/ -6 X X X81", 56, 9},
+		{"pending strike", "X 3", 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Breaking down %s... (expected strike bonus %d, spare bonus %d)", tt.notation, tt.strike, tt.spare)
+			game, _ := ParseNotation(tt.notation)
+
+			if strike, spare := game.BonusBreakdown(); strike != tt.strike || spare != tt.spare {
+				t.Errorf("Expected bonuses of %d and %d, but they were %d and %d instead.", tt.strike, tt.spare, strike, spare)
+			}
+		})
+	}
+}
+
+func TestIsClean(t *testing.T) {
+	tests := []struct {
+		name     string
+		notation string
+		want     bool
+	}{
+		{"all spares", "9/ 9/ 9/ 9/ 9/ 9/ 9/ 9/ 9/ 9/9", true},
+		{"strikes and spares", "X 7/ X 8/ X 9/ X 6/ X X9/", true},
+		{"one open frame", "9/ 9/ 9/ 9/ 9- 9/ 9/ 9/ 9/ 9/9", false},
+		{"open tenth frame", "X X X X X X X X X 9-", false},
+		{"incomplete", "9/ 9/ 9/ X", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Checking %s... (expected clean: %t)", tt.notation, tt.want)
+			game, err := ParseNotation(tt.notation)
+			if err != nil {
+				t.Fatalf("Expected notation to parse, but it failed with %v.", err)
+			}
+
+			if clean := game.IsClean(); clean != tt.want {
+				t.Errorf("Expected clean to be %t, but it was %t instead.", tt.want, clean)
+			}
+		})
+	}
+}
+
+func TestStatsHandler(t *testing.T) {
+	t.Log("GETting the stats of a strike... (expected strikes: 1, streak: 1)")
+	game := NewGame()
+	game.rollStrike()
+	rec := httptest.NewRecorder()
+	StatsHandler(game)(rec, httptest.NewRequest(http.MethodGet, "/stats", nil))
+
+	var got GameStats
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Strikes != 1 || got.Streak != 1 {
+		t.Errorf("Expected 1 strike and a streak of 1, but the stats were %+v.", got)
+	}
+}
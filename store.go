@@ -0,0 +1,543 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultPageSize and maxPageSize are the default and largest number of games
+// listed per page by GET /games.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// ErrGameNotFound is returned when a request references an unknown game ID.
+var ErrGameNotFound = errors.New("game not found")
+
+// ErrTooManyGames is returned when a game is added to a store already holding
+// as many games as it allows.
+var ErrTooManyGames = errors.New("too many games")
+
+// ErrIDsExhausted is returned when the store's IDGenerator keeps generating
+// IDs already in use.
+var ErrIDsExhausted = errors.New("no free ID")
+
+// maxIDAttempts is how many IDs the store generates before giving up with
+// ErrIDsExhausted.
+const maxIDAttempts = 10
+
+// GameStore holds the games hosted by the server, keyed by ID. It is safe for
+// concurrent use.
+type GameStore struct {
+	mu    sync.Mutex
+	games map[string]*Game
+
+	// touched records when each game was last added or looked up.
+	touched map[string]time.Time
+
+	// onComplete is given to every game added, if set.
+	onComplete func(CompletedGame)
+
+	// now is the clock games are touched and expired by.
+	now func() time.Time
+
+	// series holds the games grouped into each series, by series ID.
+	series map[string][]seriesGame
+
+	// maxGames is the most games the store holds at once, or 0 for no limit.
+	maxGames int
+
+	// ids generates the IDs of games and series.
+	ids IDGenerator
+
+	// events carries the score events of every game added.
+	events *Broker
+}
+
+// NewGameStore allocates an empty game store.
+func NewGameStore() *GameStore {
+	return &GameStore{
+		games:   make(map[string]*Game),
+		touched: make(map[string]time.Time),
+		now:     time.Now,
+		series:  make(map[string][]seriesGame),
+		ids:     IDFunc(newGameID),
+		events:  new(Broker),
+	}
+}
+
+// Create starts a new game configured by opts, adds it to the store, and
+// returns its ID. It returns ErrTooManyGames if the store is full, and
+// ErrIDsExhausted if no free ID could be generated.
+func (s *GameStore) Create(opts ...GameOption) (string, *Game, error) {
+	gm := NewGame(opts...)
+	id, err := s.Add(gm)
+	if err != nil {
+		return "", nil, err
+	}
+	return id, gm, nil
+}
+
+// SetIDGenerator has the store generate the IDs of games and series added
+// from now on with ids.
+func (s *GameStore) SetIDGenerator(ids IDGenerator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids = ids
+}
+
+// SetMaxGames limits the store to holding n games at once, so that adding
+// another fails with ErrTooManyGames until some have been evicted. An n of 0
+// removes the limit.
+func (s *GameStore) SetMaxGames(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxGames = n
+}
+
+// OnComplete has fn called with a game's final state the first time each game
+// added from now on is complete, as WithOnComplete does.
+func (s *GameStore) OnComplete(fn func(CompletedGame)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onComplete = fn
+}
+
+// Add stores an existing game, such as one seeded by NewGameFromRolls, under
+// a new ID and returns the ID. It returns ErrTooManyGames if the store is
+// full, and ErrIDsExhausted if no free ID could be generated.
+func (s *GameStore) Add(gm *Game) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.maxGames > 0 && len(s.games) >= s.maxGames {
+		return "", fmt.Errorf("%w: the limit is %d", ErrTooManyGames, s.maxGames)
+	}
+	id, err := s.newID(func(id string) bool { return s.games[id] != nil })
+	if err != nil {
+		return "", err
+	}
+	gm.mu.Lock()
+	gm.id = id
+	gm.events = s.events
+	if s.onComplete != nil {
+		gm.onComplete = s.onComplete
+	}
+	gm.mu.Unlock()
+	s.games[id] = gm
+	s.touched[id] = s.now()
+	activeGames.Inc()
+	slog.Info("game created", "id", id)
+	return id, nil
+}
+
+// newID generates an ID for which taken reports false, retrying up to
+// maxIDAttempts times before returning ErrIDsExhausted. The caller must hold
+// s.mu.
+func (s *GameStore) newID(taken func(string) bool) (string, error) {
+	for x := 0; x < maxIDAttempts; x++ {
+		id := s.ids.NewID()
+		if !taken(id) {
+			return id, nil
+		}
+		slog.Debug("ID collision", "id", id)
+	}
+	return "", fmt.Errorf("%w: %d tries were all taken", ErrIDsExhausted, maxIDAttempts)
+}
+
+// Events returns the Broker the stored games publish their score events on,
+// for subscribing to a game by ID.
+func (s *GameStore) Events() *Broker {
+	return s.events
+}
+
+// Get returns the game stored under id, or ErrGameNotFound, and counts as
+// activity on the game.
+func (s *GameStore) Get(id string) (*Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	gm, ok := s.games[id]
+	if !ok {
+		return nil, ErrGameNotFound
+	}
+	s.touched[id] = s.now()
+	return gm, nil
+}
+
+// Delete removes the game stored under id, freeing its place in the store,
+// or returns ErrGameNotFound.
+func (s *GameStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.games[id]; !ok {
+		return ErrGameNotFound
+	}
+	delete(s.games, id)
+	delete(s.touched, id)
+	activeGames.Dec()
+	slog.Info("game deleted", "id", id)
+	return nil
+}
+
+// Sweep evicts every game that has been idle for longer than ttl and returns
+// how many were evicted.
+func (s *GameStore) Sweep(ttl time.Duration) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	evicted := 0
+	for id, touched := range s.touched {
+		if s.now().Sub(touched) > ttl {
+			delete(s.games, id)
+			delete(s.touched, id)
+			activeGames.Dec()
+			slog.Debug("game expired", "id", id)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// minSweepInterval is the shortest interval StartSweeper sweeps at.
+const minSweepInterval = time.Millisecond
+
+// StartSweeper sweeps games idle for longer than ttl every interval, in the
+// background, until the returned function is called. The function waits for
+// the sweeper to stop. An interval shorter than minSweepInterval, including a
+// zero or negative one, is raised to it.
+func (s *GameStore) StartSweeper(ttl, interval time.Duration) (stop func()) {
+	interval = max(interval, minSweepInterval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				s.Sweep(ttl)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}
+}
+
+// GameListing is one game in a page of stored games.
+type GameListing struct {
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Score    int    `json:"score"`
+	Complete bool   `json:"complete"`
+}
+
+// List returns up to limit stored games ordered by ID, skipping the first
+// offset of them, and how many games are stored in all.
+func (s *GameStore) List(offset, limit int) (page []GameListing, total int) {
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.games))
+	for id := range s.games {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	total = len(ids)
+	start := min(offset, total)
+	ids = ids[start : start+min(limit, total-start)]
+	games := make([]*Game, len(ids))
+	for x, id := range ids {
+		games[x] = s.games[id]
+	}
+	s.mu.Unlock()
+
+	page = make([]GameListing, len(ids))
+	for x, gm := range games {
+		page[x] = GameListing{ID: ids[x], Name: gm.Name(), Score: gm.Score(), Complete: gm.IsComplete()}
+	}
+	return page, total
+}
+
+// LeaderboardEntry is one game's standing on the leaderboard.
+type LeaderboardEntry struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Score int    `json:"score"`
+}
+
+// Leaderboard returns every stored game ordered by score, highest first.
+// Games with equal scores are ordered by ID.
+func (s *GameStore) Leaderboard() []LeaderboardEntry {
+	s.mu.Lock()
+	entries := make([]LeaderboardEntry, 0, len(s.games))
+	games := make([]*Game, 0, len(s.games))
+	for id, gm := range s.games {
+		entries = append(entries, LeaderboardEntry{ID: id})
+		games = append(games, gm)
+	}
+	s.mu.Unlock()
+
+	for x, gm := range games {
+		entries[x].Name = gm.Name()
+		entries[x].Score = gm.Score()
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries
+}
+
+// PlayerSummary aggregates a player's completed games.
+type PlayerSummary struct {
+	Name     string  `json:"name"`
+	Games    int     `json:"games"`
+	Average  float64 `json:"average"`
+	HighGame int     `json:"high_game"`
+}
+
+// PlayerSummary returns the number of completed games bowled under name,
+// with their average and highest scores. Games still in progress are left
+// out.
+func (s *GameStore) PlayerSummary(name string) PlayerSummary {
+	s.mu.Lock()
+	games := make([]*Game, 0, len(s.games))
+	for _, gm := range s.games {
+		games = append(games, gm)
+	}
+	s.mu.Unlock()
+
+	summary := PlayerSummary{Name: name}
+	total := 0
+	for _, gm := range games {
+		if gm.Name() != name || !gm.IsComplete() {
+			continue
+		}
+		score := gm.Score()
+		summary.Games++
+		total += score
+		if score > summary.HighGame {
+			summary.HighGame = score
+		}
+	}
+	if summary.Games > 0 {
+		summary.Average = float64(total) / float64(summary.Games)
+	}
+	return summary
+}
+
+// IDGenerator generates the IDs a GameStore stores games under. IDs must be
+// URL-safe, as they appear in paths such as /games/{id}/roll. The store
+// retries on an ID already in use, up to maxIDAttempts times, so a generator
+// need not guarantee uniqueness, only make duplicates rare.
+type IDGenerator interface {
+	NewID() string
+}
+
+// IDFunc adapts an ordinary function to an IDGenerator.
+type IDFunc func() string
+
+// NewID returns f().
+func (f IDFunc) NewID() string {
+	return f()
+}
+
+// newGameID generates a random hexadecimal game ID. It is the IDGenerator a
+// GameStore uses by default.
+func newGameID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// endpoint handlers:
+
+// GamesHandler handles the "GET /games" endpoint, which lists a page of the
+// stored games, and the "POST /games" endpoint, which creates one. The
+// optional request body may name the player with {"name": "..."},
+// {"no_tap": N} creates a no-tap game where N pins on a full rack count as a
+// strike, and {"rolls": [...]} seeds the game with rolls already made.
+func GamesHandler(store *GameStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			listGames(w, r, store)
+			return
+		case http.MethodPost:
+		default:
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		var options struct {
+			Name  string `json:"name"`
+			NoTap int    `json:"no_tap"`
+			Rolls []int  `json:"rolls"`
+		}
+		if !requireJSON(w, r) {
+			return
+		}
+		if err := decodeBody(w, r, &options); err != nil && err != io.EOF {
+			writeJSONError(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+			return
+		}
+		if options.NoTap < 0 || options.NoTap >= allPins {
+			writeJSONError(w, http.StatusBadRequest, "invalid_no_tap", "no_tap must be between 0 and 9")
+			return
+		}
+
+		gm, err := NewGameFromRolls(options.Rolls, WithNoTap(options.NoTap))
+		if err != nil {
+			writeGameError(w, err)
+			return
+		}
+		if options.Name != "" {
+			if err := gm.SetName(options.Name); err != nil {
+				writeGameError(w, err)
+				return
+			}
+		}
+		id, err := store.Add(gm)
+		if err != nil {
+			writeGameError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		response := struct {
+			ID string `json:"id"`
+		}{
+			ID: id,
+		}
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// listGames writes the page of store's games chosen by the limit and offset
+// query parameters. A missing or invalid limit is taken as defaultPageSize
+// and one above maxPageSize as maxPageSize; a missing or invalid offset is
+// taken as 0.
+func listGames(w http.ResponseWriter, r *http.Request, store *GameStore) {
+	query := r.URL.Query()
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultPageSize
+	}
+	limit = min(limit, maxPageSize)
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	page, total := store.List(offset, limit)
+	response := struct {
+		Games  []GameListing `json:"games"`
+		Total  int           `json:"total"`
+		Limit  int           `json:"limit"`
+		Offset int           `json:"offset"`
+	}{
+		Games:  page,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// GameHandler handles the "/games/{id}/roll", "/games/{id}/score",
+// "/games/{id}/rules" and "/games/{id}/export.csv" endpoints by dispatching
+// to the single-game handlers for the addressed game, and the "DELETE
+// /games/{id}" endpoint, which removes the game from the store.
+func GameHandler(store *GameStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, action, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/games/"), "/")
+		if !ok && id != "" && r.Method == http.MethodDelete {
+			if err := store.Delete(id); err != nil {
+				writeGameError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "not_found", "Not found")
+			return
+		}
+
+		gm, err := store.Get(id)
+		if err != nil {
+			writeGameError(w, err)
+			return
+		}
+
+		switch action {
+		case "roll":
+			allowMethods(RollHandler(gm), http.MethodPost)(w, r)
+		case "score":
+			allowMethods(ScoreHandler(gm), http.MethodGet)(w, r)
+		case "rules":
+			allowMethods(RulesHandler(gm), http.MethodPatch)(w, r)
+		case "name":
+			allowMethods(NameHandler(gm), http.MethodGet, http.MethodPut)(w, r)
+		case "qr":
+			allowMethods(QRHandler(gm), http.MethodGet)(w, r)
+		case "scorecard.svg":
+			allowMethods(ScoreCardSVGHandler(gm), http.MethodGet)(w, r)
+		case "export.csv":
+			allowMethods(ExportCSVHandler(id, gm), http.MethodGet)(w, r)
+		default:
+			writeJSONError(w, http.StatusNotFound, "not_found", "Not found")
+		}
+	}
+}
+
+// PlayersHandler handles the "GET /players/{name}/summary" endpoint.
+func PlayersHandler(store *GameStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name, action, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/players/"), "/")
+		if !ok || name == "" || action != "summary" {
+			writeJSONError(w, http.StatusNotFound, "not_found", "Not found")
+			return
+		}
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		json.NewEncoder(w).Encode(store.PlayerSummary(name))
+	}
+}
+
+// LeaderboardHandler handles the "GET /leaderboard" endpoint.
+func LeaderboardHandler(store *GameStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Invalid request method")
+			return
+		}
+
+		json.NewEncoder(w).Encode(store.Leaderboard())
+	}
+}
@@ -0,0 +1,470 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGameStoreKeepsGamesSeparate(t *testing.T) {
+	t.Log("Rolling in one of two stored games... (expected scores: 7 and 0)")
+	store := NewGameStore()
+	idA, a, _ := store.Create()
+	idB, _, _ := store.Create()
+	if idA == idB {
+		t.Fatalf("Expected distinct IDs, but both were %q.", idA)
+	}
+	a.Roll(7)
+
+	b, err := store.Get(idB)
+	if err != nil {
+		t.Fatalf("Expected to find game %q, but got %v.", idB, err)
+	}
+	if score := a.Score(); score != 7 {
+		t.Errorf("Expected score of 7, but it was %d instead.", score)
+	}
+	if score := b.Score(); score != 0 {
+		t.Errorf("Expected score of 0, but it was %d instead.", score)
+	}
+}
+
+func TestGameEndpoints(t *testing.T) {
+	t.Log("Creating a game, rolling a 6, and reading its score... (expected score: 6)")
+	store := NewGameStore()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/games", GamesHandler(store))
+	mux.HandleFunc("/games/", GameHandler(store))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/games", nil))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status of 201, but it was %d instead.", rec.Code)
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	json.NewDecoder(rec.Body).Decode(&created)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/games/"+created.ID+"/roll", strings.NewReader(`{"pins": 6}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status of 201, but it was %d instead.", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/games/"+created.ID+"/score", nil))
+	var got struct {
+		Score int `json:"score"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Score != 6 {
+		t.Errorf("Expected score of 6, but it was %d instead.", got.Score)
+	}
+}
+
+func TestUnknownGameID(t *testing.T) {
+	t.Log("Reading the score of a game that doesn't exist... (expected status: 404)")
+	rec := httptest.NewRecorder()
+	GameHandler(NewGameStore())(rec, httptest.NewRequest(http.MethodGet, "/games/nope/score", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status of 404, but it was %d instead.", rec.Code)
+	}
+}
+
+func TestCreateNoTapGame(t *testing.T) {
+	t.Log("Creating a 9-pin no-tap game and rolling a 9... (expected score: 10)")
+	store := NewGameStore()
+	rec := httptest.NewRecorder()
+	GamesHandler(store)(rec, httptest.NewRequest(http.MethodPost, "/games", strings.NewReader(`{"no_tap": 9}`)))
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	json.NewDecoder(rec.Body).Decode(&created)
+	game, err := store.Get(created.ID)
+	if err != nil {
+		t.Fatalf("Expected to find game %q, but got %v.", created.ID, err)
+	}
+	game.Roll(9)
+
+	if score := game.Score(); score != 10 {
+		t.Errorf("Expected score of 10, but it was %d instead.", score)
+	}
+}
+
+func TestLeaderboard(t *testing.T) {
+	t.Log("Ranking games of 0, 300 and 167... (expected order: 300, 167, 0)")
+	store := NewGameStore()
+	low, _, _ := store.Create()
+	high, perfect, _ := store.Create()
+	perfect.SetName("ann")
+	perfect.rollMany(12, 10)
+	mid, mixed, _ := store.Create()
+	mixed.rollMixedGame()
+
+	rec := httptest.NewRecorder()
+	LeaderboardHandler(store)(rec, httptest.NewRequest(http.MethodGet, "/leaderboard", nil))
+	var got []LeaderboardEntry
+	json.NewDecoder(rec.Body).Decode(&got)
+
+	want := []LeaderboardEntry{
+		{ID: high, Name: "ann", Score: 300},
+		{ID: mid, Score: 167},
+		{ID: low, Score: 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected leaderboard of %+v, but it was %+v instead.", want, got)
+	}
+}
+
+func TestLeaderboardTiesByID(t *testing.T) {
+	t.Log("Ranking three untouched games... (expected: ordered by ID)")
+	store := NewGameStore()
+	store.Create()
+	store.Create()
+	store.Create()
+
+	entries := store.Leaderboard()
+	for x := 1; x < len(entries); x++ {
+		if entries[x-1].ID > entries[x].ID {
+			t.Errorf("Expected tied games ordered by ID, but %q came before %q.", entries[x-1].ID, entries[x].ID)
+		}
+	}
+}
+
+func TestGamesHandlerSeedsRolls(t *testing.T) {
+	t.Log("POSTing /games with rolls, then an over-long seed... (expected: score 48, then status 400)")
+	store := NewGameStore()
+	rec := httptest.NewRecorder()
+	GamesHandler(store)(rec, httptest.NewRequest(http.MethodPost, "/games", strings.NewReader(`{"rolls": [10, 7, 3, 9, 0]}`)))
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	json.NewDecoder(rec.Body).Decode(&created)
+	game, err := store.Get(created.ID)
+	if err != nil {
+		t.Fatalf("Expected the game to be stored, but got %v.", err)
+	}
+	if score := game.Score(); score != 48 {
+		t.Errorf("Expected score of 48, but it was %d instead.", score)
+	}
+
+	rec = httptest.NewRecorder()
+	GamesHandler(store)(rec, httptest.NewRequest(http.MethodPost, "/games", strings.NewReader(`{"rolls": [`+strings.Repeat("0, ", 20)+`0]}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status of 400, but it was %d instead.", rec.Code)
+	}
+	if code := decodeErrorCode(t, rec); code != "invalid_game_state" {
+		t.Errorf("Expected error code invalid_game_state, but it was %q instead.", code)
+	}
+}
+
+func TestGameStoreEvictsIdleGames(t *testing.T) {
+	t.Log("Creating a game with a 20ms TTL and waiting... (expected: evicted, score 404)")
+	store := NewGameStore()
+	stop := store.StartSweeper(20*time.Millisecond, 5*time.Millisecond)
+	defer stop()
+	id, _, _ := store.Create()
+
+	time.Sleep(100 * time.Millisecond)
+	if _, err := store.Get(id); !errors.Is(err, ErrGameNotFound) {
+		t.Errorf("Expected ErrGameNotFound, but got %v instead.", err)
+	}
+	rec := httptest.NewRecorder()
+	GameHandler(store)(rec, httptest.NewRequest(http.MethodGet, "/games/"+id+"/score", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status of 404, but it was %d instead.", rec.Code)
+	}
+}
+
+func TestGameStoreSweeperZeroInterval(t *testing.T) {
+	t.Log("Starting a sweeper with a 1ns TTL and no interval... (expected: no panic, game evicted)")
+	store := NewGameStore()
+	stop := store.StartSweeper(time.Nanosecond, 0)
+	defer stop()
+	id, _, _ := store.Create()
+
+	time.Sleep(50 * time.Millisecond)
+	if _, err := store.Get(id); !errors.Is(err, ErrGameNotFound) {
+		t.Errorf("Expected ErrGameNotFound, but got %v instead.", err)
+	}
+}
+
+func TestGameStoreSweepWithClock(t *testing.T) {
+	t.Log("Sweeping idle games with a fixed clock... (expected: only the game idle past the TTL evicted)")
+	now := time.Date(2024, time.March, 1, 19, 0, 0, 0, time.UTC)
+	store := NewGameStore()
+	store.now = func() time.Time { return now }
+	idle, _, _ := store.Create()
+	now = now.Add(20 * time.Minute)
+	active, _, _ := store.Create()
+	now = now.Add(15 * time.Minute)
+
+	if evicted := store.Sweep(30 * time.Minute); evicted != 1 {
+		t.Errorf("Expected 1 game evicted, but %d were.", evicted)
+	}
+	if _, err := store.Get(idle); !errors.Is(err, ErrGameNotFound) {
+		t.Errorf("Expected the idle game to be evicted, but got %v.", err)
+	}
+	if _, err := store.Get(active); err != nil {
+		t.Errorf("Expected the active game to remain, but got %v.", err)
+	}
+}
+
+func TestGameStoreSweepKeepsActiveGames(t *testing.T) {
+	t.Log("Sweeping right after touching a game... (expected: nothing evicted)")
+	store := NewGameStore()
+	id, _, _ := store.Create()
+	store.Get(id)
+
+	if evicted := store.Sweep(time.Minute); evicted != 0 {
+		t.Errorf("Expected no games evicted, but %d were.", evicted)
+	}
+	if _, err := store.Get(id); err != nil {
+		t.Errorf("Expected the game to remain, but got %v.", err)
+	}
+}
+
+func TestPlayerSummary(t *testing.T) {
+	t.Log("Summarizing Ann's 300 and 167 games, plus one in progress... (expected: 2 games, average 233.5, high 300)")
+	store := NewGameStore()
+	_, perfect, _ := store.Create()
+	perfect.SetName("Ann")
+	perfect.rollMany(12, 10)
+	_, mixed, _ := store.Create()
+	mixed.SetName("Ann")
+	mixed.rollMixedGame()
+	_, unfinished, _ := store.Create()
+	unfinished.SetName("Ann")
+	unfinished.rollMany(3, 10)
+	_, other, _ := store.Create()
+	other.SetName("Bob")
+	other.rollMany(20, 1)
+
+	want := PlayerSummary{Name: "Ann", Games: 2, Average: 233.5, HighGame: 300}
+	if summary := store.PlayerSummary("Ann"); summary != want {
+		t.Errorf("Expected a summary of %+v, but it was %+v instead.", want, summary)
+	}
+}
+
+func TestPlayersHandler(t *testing.T) {
+	t.Log("GETting /players/Bob/summary after a 20 game... (expected: 1 game averaging 20)")
+	store := NewGameStore()
+	_, game, _ := store.Create()
+	game.SetName("Bob")
+	game.rollMany(20, 1)
+	rec := httptest.NewRecorder()
+	PlayersHandler(store)(rec, httptest.NewRequest(http.MethodGet, "/players/Bob/summary", nil))
+
+	var got PlayerSummary
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Games != 1 || got.Average != 20 || got.HighGame != 20 {
+		t.Errorf("Expected 1 game averaging 20, but the summary was %+v.", got)
+	}
+}
+
+func TestGameStoreList(t *testing.T) {
+	t.Log("Listing the second page of 10 out of 30 games... (expected: IDs 10 to 19 in order, total 30)")
+	store := NewGameStore()
+	for x := 0; x < 30; x++ {
+		store.Create()
+	}
+	all, _ := store.List(0, 30)
+
+	page, total := store.List(10, 10)
+	if total != 30 {
+		t.Errorf("Expected a total of 30, but it was %d instead.", total)
+	}
+	if len(page) != 10 || !reflect.DeepEqual(page, all[10:20]) {
+		t.Errorf("Expected games 10 to 19 of %v, but the page was %v.", all, page)
+	}
+	if !sort.SliceIsSorted(all, func(i, j int) bool { return all[i].ID < all[j].ID }) {
+		t.Errorf("Expected games ordered by ID, but they were %v.", all)
+	}
+	if page, _ := store.List(25, 10); len(page) != 5 {
+		t.Errorf("Expected a short last page of 5, but it had %d.", len(page))
+	}
+}
+
+func TestGamesHandlerList(t *testing.T) {
+	tests := []struct {
+		query         string
+		limit, offset int
+		games         int
+	}{
+		{"?limit=10&offset=10", 10, 10, 10},
+		{"", defaultPageSize, 0, 20},
+		{"?limit=500", maxPageSize, 0, 30},
+		{"?limit=abc&offset=-3", defaultPageSize, 0, 20},
+		{"?offset=40", defaultPageSize, 40, 0},
+	}
+	store := NewGameStore()
+	for x := 0; x < 30; x++ {
+		store.Create()
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			t.Logf("GETting /games%s of 30 games... (expected: %d games, limit %d, offset %d)", tt.query, tt.games, tt.limit, tt.offset)
+			rec := httptest.NewRecorder()
+			GamesHandler(store)(rec, httptest.NewRequest(http.MethodGet, "/games"+tt.query, nil))
+
+			var got struct {
+				Games  []GameListing `json:"games"`
+				Total  int           `json:"total"`
+				Limit  int           `json:"limit"`
+				Offset int           `json:"offset"`
+			}
+			json.NewDecoder(rec.Body).Decode(&got)
+			if len(got.Games) != tt.games || got.Total != 30 || got.Limit != tt.limit || got.Offset != tt.offset {
+				t.Errorf("Expected %d of 30 games at limit %d offset %d, but got %d of %d at limit %d offset %d.",
+					tt.games, tt.limit, tt.offset, len(got.Games), got.Total, got.Limit, got.Offset)
+			}
+		})
+	}
+}
+
+func TestGameStoreMaxGames(t *testing.T) {
+	t.Log("POSTing three games to a store capped at 2, then evicting one... (expected: 201, 201, 429, then 201)")
+	now := time.Now()
+	store := NewGameStore()
+	store.now = func() time.Time { return now }
+	store.SetMaxGames(2)
+	post := func() int {
+		rec := httptest.NewRecorder()
+		GamesHandler(store)(rec, httptest.NewRequest(http.MethodPost, "/games", nil))
+		return rec.Code
+	}
+
+	for x, want := range []int{http.StatusCreated, http.StatusCreated, http.StatusTooManyRequests} {
+		if code := post(); code != want {
+			t.Errorf("Expected POST %d to get status %d, but it got %d instead.", x+1, want, code)
+		}
+	}
+	if _, _, err := store.Create(); !errors.Is(err, ErrTooManyGames) {
+		t.Errorf("Expected ErrTooManyGames, but got %v instead.", err)
+	}
+
+	now = now.Add(time.Hour)
+	store.Sweep(time.Minute)
+	if code := post(); code != http.StatusCreated {
+		t.Errorf("Expected a POST after eviction to get status 201, but it got %d instead.", code)
+	}
+}
+
+func TestGameStoreDeleteFreesSlot(t *testing.T) {
+	t.Log("Filling a store capped at 2, DELETEing a game, then creating again... (expected: 429, 204, then 201)")
+	store := NewGameStore()
+	store.SetMaxGames(2)
+	id, _, _ := store.Create()
+	store.Create()
+	post := func() int {
+		rec := httptest.NewRecorder()
+		GamesHandler(store)(rec, httptest.NewRequest(http.MethodPost, "/games", nil))
+		return rec.Code
+	}
+
+	if code := post(); code != http.StatusTooManyRequests {
+		t.Errorf("Expected a POST to the full store to get status 429, but it got %d instead.", code)
+	}
+	rec := httptest.NewRecorder()
+	GameHandler(store)(rec, httptest.NewRequest(http.MethodDelete, "/games/"+id, nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected the DELETE to get status 204, but it got %d instead.", rec.Code)
+	}
+	if _, err := store.Get(id); !errors.Is(err, ErrGameNotFound) {
+		t.Errorf("Expected ErrGameNotFound for the deleted game, but got %v instead.", err)
+	}
+	if code := post(); code != http.StatusCreated {
+		t.Errorf("Expected a POST after the delete to get status 201, but it got %d instead.", code)
+	}
+
+	rec = httptest.NewRecorder()
+	GameHandler(store)(rec, httptest.NewRequest(http.MethodDelete, "/games/"+id, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected a second DELETE to get status 404, but it got %d instead.", rec.Code)
+	}
+}
+
+func TestGameStoreMaxGamesConcurrent(t *testing.T) {
+	t.Log("Creating 50 games at once in a store capped at 10... (expected: exactly 10 created)")
+	store := NewGameStore()
+	store.SetMaxGames(10)
+	var created atomic.Int32
+	var wg sync.WaitGroup
+	for x := 0; x < 50; x++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := store.Create(); err == nil {
+				created.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := created.Load(); n != 10 {
+		t.Errorf("Expected 10 games created, but %d were.", n)
+	}
+}
+
+func TestGameStoreRetriesIDCollision(t *testing.T) {
+	t.Log("Creating two games with a generator that repeats its first ID once... (expected: IDs a and b)")
+	ids := []string{"a", "a", "b"}
+	generated := 0
+	store := NewGameStore()
+	store.SetIDGenerator(IDFunc(func() string {
+		generated++
+		return ids[generated-1]
+	}))
+
+	first, _, _ := store.Create()
+	second, _, _ := store.Create()
+	if first != "a" || second != "b" {
+		t.Errorf("Expected IDs a and b, but they were %q and %q instead.", first, second)
+	}
+	if generated != 3 {
+		t.Errorf("Expected 3 IDs generated, but there were %d.", generated)
+	}
+}
+
+func TestGameStoreIDsExhausted(t *testing.T) {
+	t.Log("Creating a game and a series with a generator stuck on a taken ID... (expected: ErrIDsExhausted, then 500)")
+	store := NewGameStore()
+	store.SetIDGenerator(IDFunc(func() string { return "a" }))
+	store.Create()
+
+	if _, _, err := store.Create(); !errors.Is(err, ErrIDsExhausted) {
+		t.Errorf("Expected ErrIDsExhausted, but got %v instead.", err)
+	}
+	store.CreateSeries("a")
+	if _, err := store.CreateSeries("a"); !errors.Is(err, ErrIDsExhausted) {
+		t.Errorf("Expected ErrIDsExhausted for a series, but got %v instead.", err)
+	}
+	rec := httptest.NewRecorder()
+	GamesHandler(store)(rec, httptest.NewRequest(http.MethodPost, "/games", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status of 500, but it was %d instead.", rec.Code)
+	}
+	if _, total := store.List(0, 10); total != 1 {
+		t.Errorf("Expected one game stored, but there were %d.", total)
+	}
+}
+
+func TestNewGameIDIsURLSafe(t *testing.T) {
+	t.Log("Generating a game ID... (expected: 16 URL-safe characters)")
+	id := newGameID()
+	if len(id) != 16 || url.PathEscape(id) != id {
+		t.Errorf("Expected 16 URL-safe characters, but the ID was %q.", id)
+	}
+}
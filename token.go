@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+// ErrInvalidToken is returned by GameFromToken for a token that is malformed,
+// fails its checksum, or holds rolls that aren't a legal game.
+var ErrInvalidToken = errors.New("invalid game token")
+
+const (
+	// tokenVersion is the first byte of every token, so the format can change.
+	tokenVersion = 1
+
+	// tokenCustomRules marks a token for a game with rules that aren't in
+	// tokenRulesets; such tokens can't be restored.
+	tokenCustomRules = 0xff
+)
+
+// tokenRulesets lists the rulesets a token can name, by index.
+var tokenRulesets = []Rules{TenPin, FivePin}
+
+// Token returns a compact, URL-safe token holding the game's rules and rolls,
+// which GameFromToken turns back into the game. The token carries a checksum
+// against corruption, but isn't signed: a client can forge any legal game.
+func (gm *Game) Token() string {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	ruleset := byte(tokenCustomRules)
+	for x, rules := range tokenRulesets {
+		if rules.Name == gm.rules.Name {
+			ruleset = byte(x)
+		}
+	}
+
+	data := []byte{tokenVersion, ruleset, byte(gm.noTap)}
+	for _, pins := range gm.rolls[:gm.current] {
+		data = append(data, byte(pins))
+	}
+	data = binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// GameFromToken restores a game from a token made by Token. It returns
+// ErrInvalidToken if the token can't be decoded, its checksum doesn't match,
+// or its rolls aren't a legal game.
+func GameFromToken(tok string) (*Game, error) {
+	data, err := base64.RawURLEncoding.DecodeString(tok)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if len(data) < 3+crc32.Size {
+		return nil, fmt.Errorf("%w: %d bytes is too short", ErrInvalidToken, len(data))
+	}
+	body, sum := data[:len(data)-crc32.Size], data[len(data)-crc32.Size:]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(sum) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidToken)
+	}
+	if body[0] != tokenVersion {
+		return nil, fmt.Errorf("%w: unknown version %d", ErrInvalidToken, body[0])
+	}
+	if int(body[1]) >= len(tokenRulesets) {
+		return nil, fmt.Errorf("%w: unknown ruleset %d", ErrInvalidToken, body[1])
+	}
+
+	rolls := make([]int, len(body)-3)
+	for x, pins := range body[3:] {
+		rolls[x] = int(pins)
+	}
+	gm, err := NewGameFromRolls(rolls, WithRules(tokenRulesets[body[1]]), WithNoTap(int(body[2])))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return gm, nil
+}
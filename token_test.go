@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenRoundTrip(t *testing.T) {
+	t.Log("Restoring the mixed game from its token... (expected: same rolls and score)")
+	game := NewGame()
+	game.rollMixedGame()
+
+	restored, err := GameFromToken(game.Token())
+	if err != nil {
+		t.Fatalf("Expected the token to restore, but got %v.", err)
+	}
+	if !reflect.DeepEqual(restored.Rolls(), game.Rolls()) || restored.Score() != 167 {
+		t.Errorf("Expected rolls %v scoring 167, but got %v scoring %d.", game.Rolls(), restored.Rolls(), restored.Score())
+	}
+}
+
+func TestTokenKeepsRules(t *testing.T) {
+	t.Log("Restoring a five-pin game from its token... (expected: five-pin rules)")
+	game := NewGame(WithRules(FivePin))
+	game.Roll(15)
+
+	restored, err := GameFromToken(game.Token())
+	if err != nil {
+		t.Fatalf("Expected the token to restore, but got %v.", err)
+	}
+	if rules := restored.Rules(); rules.Name != FivePin.Name {
+		t.Errorf("Expected five-pin rules, but they were %+v instead.", rules)
+	}
+}
+
+func TestGameFromMalformedToken(t *testing.T) {
+	game := NewGame()
+	game.Roll(7)
+	tampered, _ := base64.RawURLEncoding.DecodeString(game.Token())
+	tampered[3] = 9
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "not a token!"},
+		{"too short", "AQ"},
+		{"tampered", base64.RawURLEncoding.EncodeToString(tampered)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Logf("Restoring a %s token... (expected: ErrInvalidToken)", tt.name)
+			if _, err := GameFromToken(tt.token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Expected ErrInvalidToken, but got %v instead.", err)
+			}
+		})
+	}
+}
+
+func TestRollHandlerWithToken(t *testing.T) {
+	t.Log("POSTing a 4 to /roll with a token holding X 3... (expected: score 24 and a new token, shared game untouched)")
+	shared := NewGame()
+	carried := NewGame()
+	carried.rollStrike()
+	carried.Roll(3)
+	rec := httptest.NewRecorder()
+	RollHandler(shared)(rec, httptest.NewRequest(http.MethodPost, "/roll?token="+carried.Token(), strings.NewReader(`{"pins": 4}`)))
+
+	var got struct {
+		Score int    `json:"score"`
+		Token string `json:"token"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Score != 24 {
+		t.Errorf("Expected score of 24, but it was %d instead.", got.Score)
+	}
+	if next, err := GameFromToken(got.Token); err != nil || len(next.Rolls()) != 3 {
+		t.Errorf("Expected a token holding 3 rolls, but got %q (%v).", got.Token, err)
+	}
+	if rolls := shared.Rolls(); len(rolls) != 0 {
+		t.Errorf("Expected the shared game to be untouched, but it had %v.", rolls)
+	}
+
+	rec = httptest.NewRecorder()
+	RollHandler(shared)(rec, httptest.NewRequest(http.MethodPost, "/roll?token=bogus", strings.NewReader(`{"pins": 4}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status of 400 for a bad token, but it was %d instead.", rec.Code)
+	}
+}
+
+func TestRollHandlerTokenThenShared(t *testing.T) {
+	t.Log("POSTing a token roll and then a plain roll to one handler (expected: the plain roll lands on the shared game)")
+	shared := NewGame()
+	carried := NewGame()
+	carried.rollStrike()
+	handler := RollHandler(shared)
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/roll?token="+carried.Token(), strings.NewReader(`{"pins": 3}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status of 201 for the token roll, but it was %d instead.", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/roll", strings.NewReader(`{"pins": 4}`)))
+	var got struct {
+		Score int    `json:"score"`
+		Token string `json:"token"`
+	}
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got.Score != 4 || got.Token != "" {
+		t.Errorf("Expected score of 4 and no token, but got %d and %q instead.", got.Score, got.Token)
+	}
+	if rolls := shared.Rolls(); !reflect.DeepEqual(rolls, []int{4}) {
+		t.Errorf("Expected the shared game to hold [4], but it had %v instead.", rolls)
+	}
+	if rolls := carried.Rolls(); !reflect.DeepEqual(rolls, []int{10}) {
+		t.Errorf("Expected the carried game to hold [10], but it had %v instead.", rolls)
+	}
+}
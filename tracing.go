@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName names the tracer the scoring endpoints start their spans with.
+// It comes from the global tracer provider, which is a no-op until one is
+// installed with otel.SetTracerProvider.
+const tracerName = "github.com/shaneavelino/bowling-api"
+
+// Span attributes recorded by the scoring endpoints.
+const (
+	attrGameID = attribute.Key("game.id")
+	attrPins   = attribute.Key("roll.pins")
+	attrScore  = attribute.Key("game.score")
+)
+
+// startSpan starts a server span named name for r, continuing any trace
+// propagated in its headers.
+func startSpan(r *http.Request, name string) (context.Context, trace.Span) {
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer))
+}
+
+// endSpan records err, if any, on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
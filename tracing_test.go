@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordSpans installs a tracer provider recording every span ended until the
+// test finishes.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+func TestRollHandlerSpan(t *testing.T) {
+	t.Log("POSTing a roll of 7 inside a propagated trace... (expected: a roll span with roll.pins 7 in that trace)")
+	recorder := recordSpans(t)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator()) })
+	game := NewGame()
+	req := httptest.NewRequest(http.MethodPost, "/roll", strings.NewReader(`{"pins": 7}`))
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	RollHandler(game)(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "roll" {
+		t.Fatalf("Expected one roll span, but got %d spans.", len(spans))
+	}
+	attrs := map[string]int64{}
+	for _, kv := range spans[0].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsInt64()
+	}
+	if attrs["roll.pins"] != 7 || attrs["game.score"] != 7 {
+		t.Errorf("Expected roll.pins and game.score of 7, but the attributes were %v.", spans[0].Attributes())
+	}
+	if trace := spans[0].SpanContext().TraceID().String(); trace != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected the span to continue the propagated trace, but its trace ID was %s.", trace)
+	}
+}
+
+func TestScoreHandlerSpan(t *testing.T) {
+	t.Log("GETting the score after a strike... (expected: a score span with game.score 10)")
+	recorder := recordSpans(t)
+	game := NewGame()
+	game.rollStrike()
+	ScoreHandler(game)(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/score", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "score" {
+		t.Fatalf("Expected one score span, but got %d spans.", len(spans))
+	}
+	for _, kv := range spans[0].Attributes() {
+		if kv.Key == attrScore && kv.Value.AsInt64() != 10 {
+			t.Errorf("Expected game.score of 10, but it was %d instead.", kv.Value.AsInt64())
+		}
+	}
+}
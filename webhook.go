@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// CompletedGame is the final state of a game, as delivered to a webhook once
+// the game is complete.
+type CompletedGame struct {
+	ID     string `json:"id"`
+	Score  int    `json:"score"`
+	Frames []int  `json:"frames"`
+}
+
+// Webhook POSTs completed games to a URL as JSON. Deliveries that fail are
+// retried with exponential backoff.
+type Webhook struct {
+	url      string
+	client   *http.Client
+	attempts int
+	backoff  time.Duration
+}
+
+// NewWebhook returns a webhook posting to url, making up to attempts tries
+// per game and waiting backoff before the first retry, doubling each time.
+func NewWebhook(url string, attempts int, backoff time.Duration) *Webhook {
+	return &Webhook{
+		url:      url,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		attempts: attempts,
+		backoff:  backoff,
+	}
+}
+
+// Notify delivers game in the background, so it never holds up a roll.
+// Failures are logged rather than returned.
+func (wh *Webhook) Notify(game CompletedGame) {
+	go func() {
+		if err := wh.deliver(game); err != nil {
+			slog.Error("webhook delivery failed", "game", game.ID, "url", wh.url, "error", err)
+		}
+	}()
+}
+
+// deliver POSTs game until the receiver accepts it with a 2xx status or the
+// attempts run out, returning the last failure.
+func (wh *Webhook) deliver(game CompletedGame) error {
+	body, err := json.Marshal(game)
+	if err != nil {
+		return err
+	}
+	backoff := wh.backoff
+	for attempt := 1; ; attempt++ {
+		err = wh.post(body)
+		if err == nil || attempt >= wh.attempts {
+			return err
+		}
+		slog.Warn("webhook delivery failed, retrying", "game", game.ID, "attempt", attempt, "error", err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post makes a single delivery attempt.
+func (wh *Webhook) post(body []byte) error {
+	resp, err := wh.client.Post(wh.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
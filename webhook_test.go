@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookFiresOnceOnCompletion(t *testing.T) {
+	t.Log("Bowling a perfect game in the store, undoing and rerolling the last ball... (expected: exactly one call)")
+	calls := make(chan CompletedGame, 4)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var game CompletedGame
+		json.NewDecoder(r.Body).Decode(&game)
+		calls <- game
+	}))
+	defer receiver.Close()
+
+	store := NewGameStore()
+	store.OnComplete(NewWebhook(receiver.URL, 1, time.Millisecond).Notify)
+	id, game, _ := store.Create()
+	game.rollMany(11, 10)
+	select {
+	case <-calls:
+		t.Fatal("Expected no call before the tenth frame resolved, but there was one.")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	game.rollStrike()
+	game.Undo()
+	game.rollStrike()
+
+	select {
+	case got := <-calls:
+		if got.ID != id || got.Score != 300 || len(got.Frames) != framesPerGame {
+			t.Errorf("Expected game %s with score 300 over 10 frames, but got %+v.", id, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the webhook to be called, but it wasn't.")
+	}
+	select {
+	case got := <-calls:
+		t.Errorf("Expected exactly one call, but got another: %+v.", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	t.Log("Delivering to a receiver that fails twice... (expected: 3 attempts, then success)")
+	var attempts atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer receiver.Close()
+
+	if err := NewWebhook(receiver.URL, 3, time.Millisecond).deliver(CompletedGame{ID: "abc"}); err != nil {
+		t.Errorf("Expected the third attempt to succeed, but got %v.", err)
+	}
+	if n := attempts.Load(); n != 3 {
+		t.Errorf("Expected 3 attempts, but there were %d instead.", n)
+	}
+}
+
+func TestWebhookGivesUp(t *testing.T) {
+	t.Log("Delivering to a receiver that always fails... (expected: an error after 2 attempts)")
+	var attempts atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	if err := NewWebhook(receiver.URL, 2, time.Millisecond).deliver(CompletedGame{ID: "abc"}); err == nil {
+		t.Error("Expected an error, but there was none.")
+	}
+	if n := attempts.Load(); n != 2 {
+		t.Errorf("Expected 2 attempts, but there were %d instead.", n)
+	}
+}
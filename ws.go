@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// upgrader upgrades "GET /ws" requests to WebSocket connections.
+var upgrader = websocket.Upgrader{}
+
+// wsScore is pushed to a WebSocket client after each successful roll.
+type wsScore struct {
+	Score  int   `json:"score"`
+	Frames []int `json:"frames"`
+}
+
+// wsError is pushed to a WebSocket client when a message cannot be applied.
+type wsError struct {
+	Error string `json:"error"`
+}
+
+// endpoint handlers:
+
+// WebSocketHandler handles the "GET /ws" endpoint. Each {"pins": N} message
+// from the client rolls the ball, and the resulting score and frame
+// breakdown are sent back, as they are after rolls made by anyone else.
+// Invalid messages are answered with an error message and the connection
+// stays open until the client or the request's context closes it.
+func WebSocketHandler(gm *Game) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		events, unsubscribe := gm.Subscribe()
+		defer unsubscribe()
+
+		// Messages are read on their own goroutine so that this one is the
+		// connection's only writer. Errors to report go to rejected, which
+		// is closed once the client closes the connection.
+		rejected := make(chan wsError)
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			defer close(rejected)
+			for {
+				_, data, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+
+				var roll struct {
+					Pins int `json:"pins"`
+				}
+				if json.Unmarshal(data, &roll) != nil {
+					err = errors.New("Invalid message")
+				} else {
+					err = gm.Roll(roll.Pins)
+				}
+				if err == nil {
+					continue
+				}
+				select {
+				case rejected <- wsError{Error: err.Error()}:
+				case <-done:
+					return
+				}
+			}
+		}()
+
+		for {
+			var msg any
+			select {
+			case <-r.Context().Done():
+				return
+			case ev := <-events:
+				msg = wsScore{Score: ev.Score, Frames: gm.FrameScores()}
+			case e, ok := <-rejected:
+				if !ok {
+					return
+				}
+				msg = e
+			}
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+	}
+}
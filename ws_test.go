@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestWebSocketRolls(t *testing.T) {
+	t.Log("Rolling a 7, an 11, then a 2 over a WebSocket... (expected scores: 7, error, 9)")
+	game := NewGame()
+	server := httptest.NewServer(WebSocketHandler(game))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Expected to connect, but got %v.", err)
+	}
+	defer conn.Close()
+
+	var got struct {
+		wsScore
+		wsError
+	}
+	conn.WriteJSON(map[string]int{"pins": 7})
+	conn.ReadJSON(&got)
+	if got.Score != 7 {
+		t.Errorf("Expected score of 7, but it was %d instead.", got.Score)
+	}
+
+	got.Error = ""
+	conn.WriteJSON(map[string]int{"pins": 11})
+	conn.ReadJSON(&got)
+	if got.Error == "" {
+		t.Error("Expected an error message for 11 pins, but got none.")
+	}
+
+	conn.WriteJSON(map[string]int{"pins": 2})
+	if err := conn.ReadJSON(&got); err != nil {
+		t.Fatalf("Expected the socket to stay open after an error, but got %v.", err)
+	}
+	if got.Score != 9 {
+		t.Errorf("Expected score of 9, but it was %d instead.", got.Score)
+	}
+	if want := []int{9}; !reflect.DeepEqual(got.Frames, want) {
+		t.Errorf("Expected frames of %v, but they were %v instead.", want, got.Frames)
+	}
+
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
+
+func TestWebSocketClosesOnCancel(t *testing.T) {
+	t.Log("Cancelling the server's request contexts... (expected: connection closed)")
+	ctx, cancel := context.WithCancel(context.Background())
+	server := httptest.NewUnstartedServer(WebSocketHandler(NewGame()))
+	server.Config.BaseContext = func(net.Listener) context.Context { return ctx }
+	server.Start()
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Expected to connect, but got %v.", err)
+	}
+	defer conn.Close()
+
+	cancel()
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, _, err := conn.ReadMessage(); err == nil || isTimeout(err) {
+		t.Errorf("Expected the server to close the connection, but reading returned %v.", err)
+	}
+}
+
+// isTimeout reports whether err is a network timeout.
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}